@@ -1,21 +1,12 @@
 package form3
 
-import (
-	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
-)
+import "sync"
 
 const (
 	organisationAccountsBasePath = "/v1/organisation/accounts"
 	typ                          = "accounts"
 )
 
-type accountRoot struct {
-	Data Account `json:"data,omitempty"`
-}
-
 // Account describes a registered bank account.
 type Account struct {
 	// ID is a mandatory, UUID version 4 field. It identifies bank account within a system.
@@ -50,117 +41,190 @@ type AccountAttributes struct {
 	Title                   string `json:"title,omitempty"`
 }
 
-type accountRequestRoot struct {
-	Data AccountRequest `json:"data,omitempty"`
-}
-
-type accountListRoot struct {
-	Data  []Account `json:"data,omitempty"`
-	Links struct {
-		Next     string `json:"next,omitempty"`
-		Previous string `json:"prev,omitempty"`
-		First    string `json:"first,omitempty"`
-		Last     string `json:"last,omitempty"`
-		Self     string `json:"self,omitempty"`
-	} `json:"links,omitempty"`
-}
-
 type AccountRequest struct {
 	// ID is a mandatory, UUID version 4 field. It identifies bank account within a system.
 	ID string `json:"id,omitempty"`
 	// OrganisationID is a mandatory UUID version 4 field. It identifies organisation by which the bank account has been
 	// created.
-	OrganisationID string            `json:"organisation_id"`
-	Type           string            `json:"type,omitempty"`
-	Attributes     AccountAttributes `json:"attributes"`
+	OrganisationID string `json:"organisation_id"`
+	Type           string `json:"type,omitempty"`
+	// Version is mandatory for updates and must match the current version of the account.
+	Version    int               `json:"version"`
+	Attributes AccountAttributes `json:"attributes"`
 }
 
 type AccountService struct {
-	client *Client
+	client  *Client
+	fetches flightGroup
 }
 
-// Create a new organization account. It takes AccountRequest as an argument and returns Account or an error for network
-// problem, and for non-2xx server statuses.
+// AccountFilter narrows down a list of accounts. Empty fields are not used for filtering.
+type AccountFilter struct {
+	OrganisationID string
+	Country        string
+	BankID         string
+	BankIDCode     string
+	AccountNumber  string
+	Iban           string
+}
+
+// FetchResult is a result of fetching a single account by FetchMany.
+type FetchResult struct {
+	ID      string
+	Account Account
+	Err     error
+}
+
+// Create a new organization account. Requests without an organisation ID are created in Client.OrganisationID. It takes
+// AccountRequest as an argument and returns Account or an error for network problem, and for non-2xx server statuses.
 func (a *AccountService) Create(createReq AccountRequest) (Account, error) {
 	if createReq.Type == "" {
 		createReq.Type = typ
 	}
-
-	req, err := a.client.newRequest(http.MethodPost, &url.URL{Path: organisationAccountsBasePath}, accountRequestRoot{createReq})
-	if err != nil {
-		return Account{}, err
+	if createReq.OrganisationID == "" {
+		createReq.OrganisationID = a.client.OrganisationID
 	}
 
-	result := accountRoot{}
-	err = a.client.do(req, &result)
+	account := Account{}
+	err := a.client.createResource(organisationAccountsBasePath, createReq, &account)
+	a.invalidate(createReq.ID)
 
-	return result.Data, err
+	return account, err
 }
 
-// Fetch an Account based on ID. Returns an account or an error for network problem, and for non-2xx server statuses.
+// Fetch an Account based on ID. Accounts are served from Client.AccountCache if there is one, and concurrent fetches of
+// the same account share a single request. Returns an account or an error for network problem, and for non-2xx server
+// statuses.
 func (a *AccountService) Fetch(id string) (Account, error) {
-	fetchAccountPath := fmt.Sprintf("%s/%s", organisationAccountsBasePath, id)
-	req, err := a.client.newRequest(http.MethodGet, &url.URL{Path: fetchAccountPath}, nil)
-	if err != nil {
-		return Account{}, err
+	return a.fetches.do(id, func() (Account, error) {
+		return a.fetch(id)
+	})
+}
+
+// FetchMany fetches accounts with at most concurrency requests at a time, one at a time if concurrency is below 1.
+// Returns results in the order of ids, each with the account or an error for network problem, and for non-2xx server
+// statuses.
+func (a *AccountService) FetchMany(ids []string, concurrency int) []FetchResult {
+	if concurrency < 1 {
+		concurrency = 1
 	}
 
-	result := accountRoot{}
-	err = a.client.do(req, &result)
+	results := make([]FetchResult, len(ids))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < concurrency && w < len(ids); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				account, err := a.Fetch(ids[idx])
+				results[idx] = FetchResult{ID: ids[idx], Account: account, Err: err}
+			}
+		}()
+	}
 
-	return result.Data, err
+	for idx := range ids {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
 }
 
-// Delete an account. Returns error for network problem, and for non-2xx server statuses.
-func (a *AccountService) Delete(id string, version int) error {
-	deleteAccountPath := fmt.Sprintf("%s/%s", organisationAccountsBasePath, id)
+func (a *AccountService) fetch(id string) (Account, error) {
+	path := resourcePath(organisationAccountsBasePath, id)
+
+	cache := a.client.AccountCache
+	if cache == nil {
+		account := Account{}
+		err := a.client.fetchResource(path, &account)
 
-	deleteQuery := url.Values{
-		"version": {strconv.Itoa(version)},
+		return account, err
 	}
 
-	req, err := a.client.newRequest(http.MethodDelete, &url.URL{Path: deleteAccountPath, RawQuery: deleteQuery.Encode()}, nil)
-	if err != nil {
-		return err
+	cached, fresh, token := cache.get(id)
+	if fresh {
+		return cached.account, nil
 	}
 
-	err = a.client.do(req, nil)
+	account := Account{}
+	fetched, notModified, err := a.client.fetchConditionally(path, cached.validators, &account)
+	switch {
+	case err != nil:
+		if IsNotFound(err) {
+			cache.Invalidate(id)
+		}
+		return Account{}, err
+	case notModified:
+		account, fetched = cached.account, cached.validators
+	}
 
-	return err
-}
+	cache.put(id, account, fetched, token)
 
-// List accounts. Accepts pagination options as an argument. Returns list of accounts, true if there are more pages with
-// accounts or an error for network problem, and for non-2xx server statuses.
-func (a *AccountService) List(options ListOptions) ([]Account, bool, error) {
-	listQuery := a.getPagingQueryParams(options)
+	return account, nil
+}
 
-	req, err := a.client.newRequest(http.MethodGet, &url.URL{Path: organisationAccountsBasePath, RawQuery: listQuery.Encode()}, nil)
-	if err != nil {
-		return nil, false, err
+// Update attributes of an account. Only non-empty attributes are changed. The request version must match the current
+// version of the account. Returns the updated account or an error for network problem, and for non-2xx server
+// statuses.
+func (a *AccountService) Update(updateReq AccountRequest) (Account, error) {
+	if updateReq.Type == "" {
+		updateReq.Type = typ
 	}
 
-	result := accountListRoot{}
-	err = a.client.do(req, &result)
+	account := Account{}
+	err := a.client.updateResource(resourcePath(organisationAccountsBasePath, updateReq.ID), updateReq, &account)
+	a.invalidate(updateReq.ID)
+
+	return account, err
+}
+
+// Delete an account. Returns error for network problem, and for non-2xx server statuses.
+func (a *AccountService) Delete(id string, version int) error {
+	err := a.client.deleteResource(resourcePath(organisationAccountsBasePath, id), version)
+	a.invalidate(id)
 
-	return result.Data, result.Links.Next != "", err
+	return err
 }
 
-func (a *AccountService) getPagingQueryParams(options ListOptions) url.Values {
-	var (
-		pageNumber string
-		pageSize   string
-	)
+// List accounts of Client.OrganisationID, or all accounts if it is empty. Accepts pagination options as an argument.
+// Returns list of accounts, true if there are more pages with accounts or an error for network problem, and for non-2xx
+// server statuses.
+func (a *AccountService) List(options ListOptions) ([]Account, bool, error) {
+	return a.ListWhere(options, AccountFilter{})
+}
 
-	if options.PageSize == 0 {
-		pageSize = defaultPageSize
-	} else {
-		pageSize = strconv.Itoa(options.PageSize)
+// ListWhere lists accounts matching the filter, Client.OrganisationID filters accounts unless the filter sets an
+// organisation. Accepts pagination options and a filter as arguments. Returns list of
+// accounts, true if there are more pages with accounts or an error for network problem, and for non-2xx server
+// statuses.
+func (a *AccountService) ListWhere(options ListOptions, filter AccountFilter) ([]Account, bool, error) {
+	if filter.OrganisationID == "" {
+		filter.OrganisationID = a.client.OrganisationID
 	}
 
-	pageNumber = strconv.Itoa(options.Page)
-	listQuery := url.Values{
-		"page[number]": {pageNumber},
-		"page[size]":   {pageSize},
+	query := pagingQuery(options)
+	setFilter(query, "organisation_id", filter.OrganisationID)
+	setFilter(query, "country", filter.Country)
+	setFilter(query, "bank_id", filter.BankID)
+	setFilter(query, "bank_id_code", filter.BankIDCode)
+	setFilter(query, "account_number", filter.AccountNumber)
+	setFilter(query, "iban", filter.Iban)
+
+	var accounts []Account
+	hasNext, err := a.client.listResources(organisationAccountsBasePath, query, &accounts)
+
+	return accounts, hasNext, err
+}
+
+// invalidate removes a changed account from Client.AccountCache and makes later fetches send a new request rather than
+// join one in flight. Failed changes invalidate it too, as they may have been applied, or failed because the cached
+// account is stale.
+func (a *AccountService) invalidate(id string) {
+	a.fetches.forget(id)
+	if a.client.AccountCache != nil {
+		a.client.AccountCache.Invalidate(id)
 	}
-	return listQuery
 }
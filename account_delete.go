@@ -0,0 +1,119 @@
+package form3
+
+import (
+	"errors"
+	"sync"
+)
+
+// deleteAttempts is a number of times DeleteLatest fetches the account, as it may change between Fetch and Delete.
+const deleteAttempts = 3
+
+// ErrDeleteNotConfirmed is returned by DeleteWhere unless DeleteOptions confirm the delete or ask for a dry run.
+var ErrDeleteNotConfirmed = errors.New("delete of accounts is not confirmed, set Confirm or DryRun")
+
+// DeleteOptions guard and tune DeleteWhere.
+type DeleteOptions struct {
+	// Confirm must be set to delete accounts.
+	Confirm bool
+	// DryRun lists accounts which would be deleted without deleting them. It takes precedence over Confirm.
+	DryRun bool
+	// Concurrency is a number of concurrent Delete calls, 1 if not set.
+	Concurrency int
+}
+
+// DeleteSummary reports the outcome of DeleteWhere.
+type DeleteSummary struct {
+	// Matched lists IDs of accounts matching the filter in listing order.
+	Matched []string
+	// Deleted is a number of deleted accounts. Accounts deleted concurrently by someone else count as deleted.
+	Deleted int
+	// Errors holds errors of accounts which could not be deleted, by account ID.
+	Errors map[string]error
+}
+
+// DeleteLatest deletes an account in its current version. The account is fetched again if it changes before it is
+// deleted. Returns error for network problem, and for non-2xx server statuses.
+func (a *AccountService) DeleteLatest(id string) error {
+	var err error
+	for attempt := 0; attempt < deleteAttempts; attempt++ {
+		var account Account
+		if account, err = a.Fetch(id); err != nil {
+			return err
+		}
+
+		if err = a.Delete(id, account.Version); !IsConflict(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// DeleteWhere deletes all accounts matching the filter. An empty filter matches all accounts. Accounts are listed
+// before any of them is deleted, so they are not skipped by pagination. Returns ErrDeleteNotConfirmed unless options
+// confirm the delete or ask for a dry run, otherwise returns a summary or an error for network problem, and for
+// non-2xx server statuses of the list. Errors of single deletes are reported in the summary.
+func (a *AccountService) DeleteWhere(filter AccountFilter, options DeleteOptions) (DeleteSummary, error) {
+	if !options.Confirm && !options.DryRun {
+		return DeleteSummary{}, ErrDeleteNotConfirmed
+	}
+
+	var accounts []Account
+	for page, hasNext := 0, true; hasNext; page++ {
+		var list []Account
+		var err error
+
+		list, hasNext, err = a.ListWhere(ListOptions{Page: page}, filter)
+		if err != nil {
+			return DeleteSummary{}, err
+		}
+		accounts = append(accounts, list...)
+	}
+
+	summary := DeleteSummary{Matched: make([]string, 0, len(accounts)), Errors: map[string]error{}}
+	for _, account := range accounts {
+		summary.Matched = append(summary.Matched, account.ID)
+	}
+
+	if options.DryRun {
+		return summary, nil
+	}
+
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	mu := sync.Mutex{}
+	queue := make(chan Account)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for account := range queue {
+				err := a.Delete(account.ID, account.Version)
+				if IsConflict(err) {
+					err = a.DeleteLatest(account.ID)
+				}
+
+				mu.Lock()
+				if err == nil || IsNotFound(err) {
+					summary.Deleted++
+				} else {
+					summary.Errors[account.ID] = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, account := range accounts {
+		queue <- account
+	}
+	close(queue)
+	wg.Wait()
+
+	return summary, nil
+}
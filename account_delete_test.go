@@ -0,0 +1,140 @@
+package form3
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ptrsd/form3/fault"
+	"github.com/ptrsd/form3/form3test"
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+func TestAccountService_DeleteLatest(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	t.Run("When account has been updated then it is deleted in the current version", func(t *testing.T) {
+		client := NewClient(nil, server.URL)
+		account := givenUpdatedAccount(t, client)
+
+		err := client.AccountService.DeleteLatest(account.ID)
+		_, found := server.Get(form3test.AccountsPath, account.ID)
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: found, expected: false, name: "Found"},
+		})
+	})
+
+	t.Run("When account changes before delete then it is fetched again", func(t *testing.T) {
+		// Create, Update and Fetch pass, the first Delete fails with a version conflict.
+		transport := &fault.Transport{Script: []fault.Fault{
+			{}, {}, {}, fault.Status(http.StatusConflict, `{"error_message":"invalid version"}`),
+		}}
+		client := NewClient(&http.Client{Transport: transport}, server.URL)
+		account := givenUpdatedAccount(t, client)
+
+		err := client.AccountService.DeleteLatest(account.ID)
+		_, found := server.Get(form3test.AccountsPath, account.ID)
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: found, expected: false, name: "Found"},
+			{actual: transport.Calls(), expected: 6, name: "Calls"},
+		})
+	})
+
+	t.Run("When account does not exist then not found is returned", func(t *testing.T) {
+		client := NewClient(nil, server.URL)
+		id, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating UUID, %s", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: IsNotFound(client.AccountService.DeleteLatest(id)), expected: true, name: "IsNotFound"},
+		})
+	})
+}
+
+func TestAccountService_DeleteWhere(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+	french := givenStoredAccounts(t, server, "FR", 101)
+	british := givenStoredAccounts(t, server, "GB", 2)
+
+	t.Run("When delete is not confirmed then nothing is deleted", func(t *testing.T) {
+		_, err := client.AccountService.DeleteWhere(AccountFilter{Country: "FR"}, DeleteOptions{})
+
+		thenEquals(t, assertions{
+			{actual: err, expected: ErrDeleteNotConfirmed, name: "Error"},
+			{actual: len(server.Resources(form3test.AccountsPath)), expected: 103, name: "Accounts"},
+		})
+	})
+
+	t.Run("When dry run then matching accounts are reported and not deleted", func(t *testing.T) {
+		summary, err := client.AccountService.DeleteWhere(AccountFilter{Country: "FR"}, DeleteOptions{Confirm: true, DryRun: true})
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: summary.Matched, expected: french, name: "Matched"},
+			{actual: summary.Deleted, expected: 0, name: "Deleted"},
+			{actual: len(server.Resources(form3test.AccountsPath)), expected: 103, name: "Accounts"},
+		})
+	})
+
+	t.Run("When delete is confirmed then all matching accounts are deleted", func(t *testing.T) {
+		summary, err := client.AccountService.DeleteWhere(AccountFilter{Country: "FR"}, DeleteOptions{Confirm: true, Concurrency: 4})
+
+		var remaining []string
+		for _, resource := range server.Resources(form3test.AccountsPath) {
+			remaining = append(remaining, resource.ID)
+		}
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: len(summary.Matched), expected: 101, name: "Matched"},
+			{actual: summary.Deleted, expected: 101, name: "Deleted"},
+			{actual: summary.Errors, expected: map[string]error{}, name: "Errors"},
+			{actual: remaining, expected: british, name: "Remaining"},
+		})
+	})
+}
+
+func givenUpdatedAccount(t *testing.T, client *Client) Account {
+	t.Helper()
+
+	account, err := givenMinimalAccount(client)
+	if err != nil {
+		t.Fatalf("error while generating minimal account, %s", err.Error())
+	}
+
+	account, err = client.AccountService.Update(AccountRequest{ID: account.ID, Attributes: AccountAttributes{BankID: "400300"}})
+	if err != nil {
+		t.Fatalf("update account returned with error %v", err.Error())
+	}
+
+	return account
+}
+
+func givenStoredAccounts(t *testing.T, server *form3test.Server, country string, numberOfAccounts int) []string {
+	t.Helper()
+
+	attributes, _ := json.Marshal(AccountAttributes{Country: country})
+
+	ids := make([]string, 0, numberOfAccounts)
+	for idx := 0; idx < numberOfAccounts; idx++ {
+		id, orgID, err := generateIDs()
+		if err != nil {
+			t.Fatalf("error while generating IDs, %s", err.Error())
+		}
+
+		server.Put(form3test.AccountsPath, jsonapi.Resource{ID: id, OrganisationID: orgID, Type: typ, Attributes: attributes})
+		ids = append(ids, id)
+	}
+
+	return ids
+}
@@ -0,0 +1,76 @@
+package form3
+
+const (
+	accountIdentificationsBasePath = "/v1/transaction/account-identifications"
+	accountIdentificationType      = "account_identifications"
+)
+
+// MatchResult is an outcome of a Confirmation of Payee name check.
+type MatchResult string
+
+const (
+	// MatchResultFullMatch means that the name matches the name of the account holder.
+	MatchResultFullMatch MatchResult = "full_match"
+	// MatchResultCloseMatch means that the name is similar, the name of the account holder is returned as ActualName.
+	MatchResultCloseMatch MatchResult = "close_match"
+	// MatchResultNoMatch means that the name does not match or the account does not exist.
+	MatchResultNoMatch MatchResult = "no_match"
+	// MatchResultOptedOut means that the account holder has opted out of account matching.
+	MatchResultOptedOut MatchResult = "opted_out"
+)
+
+// AccountIdentification is a result of a Confirmation of Payee name check.
+type AccountIdentification struct {
+	ID             string                          `json:"id,omitempty"`
+	OrganisationID string                          `json:"organisation_id,omitempty"`
+	CreatedOn      string                          `json:"created_on,omitempty"`
+	Type           string                          `json:"type,omitempty"`
+	Attributes     AccountIdentificationAttributes `json:"attributes,omitempty"`
+}
+
+// AccountIdentificationAttributes describes the checked account, the name and the result of the check.
+type AccountIdentificationAttributes struct {
+	// Name is a mandatory name of the payee to be verified.
+	Name string `json:"name,omitempty"`
+	// AccountType is either "personal" or "business".
+	AccountType             string `json:"account_type,omitempty"`
+	AccountNumber           string `json:"account_number,omitempty"`
+	BankID                  string `json:"bank_id,omitempty"`
+	BankIDCode              string `json:"bank_id_code,omitempty"`
+	SecondaryIdentification string `json:"secondary_identification,omitempty"`
+
+	// MatchResult is set by the server.
+	MatchResult MatchResult `json:"match_result,omitempty"`
+	// ActualName is a suggested name of the account holder, returned for a close match.
+	ActualName string `json:"actual_name,omitempty"`
+}
+
+// AccountIdentificationRequest describes a name check to be performed.
+type AccountIdentificationRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies the check within a system.
+	ID             string                          `json:"id,omitempty"`
+	OrganisationID string                          `json:"organisation_id"`
+	Type           string                          `json:"type,omitempty"`
+	Attributes     AccountIdentificationAttributes `json:"attributes"`
+}
+
+type AccountIdentificationService struct {
+	client *Client
+}
+
+// Identify checks whether the name matches the holder of the account. Requests without an organisation ID are made for
+// Client.OrganisationID. Returns the check with MatchResult set or an error for network problem, and for non-2xx server
+// statuses.
+func (a *AccountIdentificationService) Identify(identifyReq AccountIdentificationRequest) (AccountIdentification, error) {
+	if identifyReq.Type == "" {
+		identifyReq.Type = accountIdentificationType
+	}
+	if identifyReq.OrganisationID == "" {
+		identifyReq.OrganisationID = a.client.OrganisationID
+	}
+
+	identification := AccountIdentification{}
+	err := a.client.createResource(accountIdentificationsBasePath, identifyReq, &identification)
+
+	return identification, err
+}
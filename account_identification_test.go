@@ -0,0 +1,106 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestAccountIdentificationService_Identify(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	holder := AccountAttributes{
+		Country:                     "GB",
+		AccountNumber:               "41426819",
+		BankID:                      "400300",
+		BankIDCode:                  "GBDSC",
+		BankAccountName:             "Samantha Holder",
+		AlternativeBankAccountNames: []string{"Sam Holder"},
+	}
+	optedOut := AccountAttributes{
+		Country:               "GB",
+		AccountNumber:         "51426819",
+		BankID:                "400300",
+		BankIDCode:            "GBDSC",
+		BankAccountName:       "Private Person",
+		AccountMatchingOptOut: true,
+	}
+	for _, attrs := range []AccountAttributes{holder, optedOut} {
+		req, err := generateAccountWithAttributes(attrs)
+		if err != nil {
+			t.Fatalf("error while generating account, %s", err.Error())
+		}
+		if _, err := client.AccountService.Create(req); err != nil {
+			t.Fatalf("create account returned with error %v", err.Error())
+		}
+	}
+
+	tests := []struct {
+		name               string
+		payee              string
+		accountNumber      string
+		expectedResult     MatchResult
+		expectedActualName string
+	}{
+		{name: "When name matches then full match", payee: "samantha  HOLDER", accountNumber: holder.AccountNumber, expectedResult: MatchResultFullMatch},
+		{name: "When alternative name matches then full match", payee: "Sam Holder", accountNumber: holder.AccountNumber, expectedResult: MatchResultFullMatch},
+		{name: "When name is similar then close match with actual name", payee: "Samanta Holdr", accountNumber: holder.AccountNumber, expectedResult: MatchResultCloseMatch, expectedActualName: "Samantha Holder"},
+		{name: "When name differs then no match", payee: "John Smith", accountNumber: holder.AccountNumber, expectedResult: MatchResultNoMatch},
+		{name: "When account does not exist then no match", payee: "Samantha Holder", accountNumber: "00000000", expectedResult: MatchResultNoMatch},
+		{name: "When holder opted out then opted out", payee: "Private Person", accountNumber: optedOut.AccountNumber, expectedResult: MatchResultOptedOut},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			id, orgID, err := generateIDs()
+			if err != nil {
+				t.Fatalf("error while generating ids, %s", err.Error())
+			}
+
+			identification, err := client.AccountIdentificationService.Identify(AccountIdentificationRequest{
+				ID:             id,
+				OrganisationID: orgID,
+				Attributes: AccountIdentificationAttributes{
+					Name:                    test.payee,
+					AccountType:             "personal",
+					AccountNumber:           test.accountNumber,
+					BankID:                  "400300",
+					BankIDCode:              "GBDSC",
+					SecondaryIdentification: "ROLL-0042",
+				},
+			})
+			if err != nil {
+				t.Fatalf("identify returned with error %v", err.Error())
+			}
+
+			thenEquals(t, assertions{
+				{actual: identification.Attributes.MatchResult, expected: test.expectedResult, name: "MatchResult"},
+				{actual: identification.Attributes.ActualName, expected: test.expectedActualName, name: "ActualName"},
+				{actual: identification.Attributes.AccountType, expected: "personal", name: "AccountType"},
+				{actual: identification.Attributes.SecondaryIdentification, expected: "ROLL-0042", name: "SecondaryIdentification"},
+			})
+		})
+	}
+
+	t.Run("When client is scoped to organisation then check is made for it", func(t *testing.T) {
+		id, orgID, err := generateIDs()
+		if err != nil {
+			t.Fatalf("error while generating ids, %s", err.Error())
+		}
+
+		identification, err := client.ForOrganisation(orgID).AccountIdentificationService.Identify(AccountIdentificationRequest{
+			ID:         id,
+			Attributes: AccountIdentificationAttributes{Name: "Samantha Holder", AccountNumber: holder.AccountNumber},
+		})
+		if err != nil {
+			t.Fatalf("identify returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: identification.OrganisationID, expected: orgID, name: "OrganisationID"},
+		})
+	})
+}
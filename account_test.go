@@ -4,30 +4,88 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"hash/fnv"
+	"io"
+	mathrand "math/rand"
+	"net/http"
 	"os"
+	"runtime"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/ptrsd/form3/cassette"
+	"github.com/ptrsd/form3/form3test"
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+const (
+	// accountsCassette holds interactions of tests against the account API, see FORM3_CASSETTE.
+	accountsCassette = "testdata/cassettes/accounts.json"
+	// cassetteLive runs tests against the account API without a cassette.
+	cassetteLive = "live"
 )
 
-var baseURL string
+var (
+	baseURL string
+	// httpClient is used by tests against the account API, nil for the default client.
+	httpClient *http.Client
+	// recorder records or replays tests against the account API, nil when they run against the live API.
+	recorder *cassette.Recorder
+	// cassetteMissing skips tests against the account API when there is no cassette to replay.
+	cassetteMissing bool
+	// randomSource generates random IDs.
+	randomSource io.Reader = rand.Reader
+)
 
+// TestMain replays tests against the account API from accountsCassette, so they run without docker-compose.
+// FORM3_CASSETTE=record records the interactions against the account API set up by docker-compose,
+// FORM3_CASSETTE=live runs the tests against it without a cassette.
 func TestMain(m *testing.M) {
 	baseURL = os.Getenv("APP_BASE_URL")
 	if baseURL == "" {
 		baseURL = defaultBaseURL
 	}
 
+	mode := os.Getenv("FORM3_CASSETTE")
+	if mode == "" {
+		mode = string(cassette.ModeReplay)
+		if _, err := os.Stat(accountsCassette); os.IsNotExist(err) {
+			cassetteMissing = true
+		}
+	}
+
+	if mode != cassetteLive && !cassetteMissing {
+		var err error
+		if recorder, err = cassette.New(accountsCassette, cassette.Mode(mode)); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		httpClient = &http.Client{Transport: recorder}
+	}
+
 	code := m.Run()
+
+	if recorder != nil {
+		if err := recorder.Save(); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			code = 1
+		}
+	}
+
 	os.Exit(code)
 }
 
 func TestAccountService_Create(t *testing.T) {
-	client := NewClient(nil, baseURL)
+	client := givenAccountAPIClient(t)
 	t.Run("When creating accounts with valid data then return new account", func(t *testing.T) {
 		accountRequest, err := generateAccountWithAttributes(AccountAttributes{Country: "GB"})
 		if err != nil {
 			t.Errorf("error while generating minimal account, %s", err.Error())
 			t.FailNow()
 		}
+		givenDeletedAfterTest(t, client, accountRequest.ID)
 
 		create, err := client.AccountService.Create(accountRequest)
 		if err != nil {
@@ -56,6 +114,7 @@ func TestAccountService_Create(t *testing.T) {
 			t.Errorf("error while generating minimal account, %s", err.Error())
 			t.FailNow()
 		}
+		givenDeletedAfterTest(t, client, accountRequest.ID)
 
 		_, err = client.AccountService.Create(accountRequest)
 		if err != nil {
@@ -90,7 +149,7 @@ func TestAccountService_Create(t *testing.T) {
 }
 
 func TestAccountService_Fetch(t *testing.T) {
-	client := NewClient(nil, baseURL)
+	client := givenAccountAPIClient(t)
 
 	t.Run("When fetching existing account then return requested account", func(t *testing.T) {
 		accountRequest, err := generateAccountWithAttributes(AccountAttributes{Country: "GB"})
@@ -98,6 +157,7 @@ func TestAccountService_Fetch(t *testing.T) {
 			t.Errorf("error while generating minimal account, %s", err.Error())
 			t.FailNow()
 		}
+		givenDeletedAfterTest(t, client, accountRequest.ID)
 
 		newAccount, err := client.AccountService.Create(accountRequest)
 		if err != nil {
@@ -146,7 +206,7 @@ func TestAccountService_Fetch(t *testing.T) {
 }
 
 func TestAccountService_Delete(t *testing.T) {
-	client := NewClient(nil, baseURL)
+	client := givenAccountAPIClient(t)
 
 	t.Run("When deleting existing account then success", func(t *testing.T) {
 		account, err := givenMinimalAccount(client)
@@ -154,6 +214,7 @@ func TestAccountService_Delete(t *testing.T) {
 			t.Errorf("error while generating minimal account, %s", err.Error())
 			t.FailNow()
 		}
+		givenDeletedAfterTest(t, client, account.ID)
 
 		err = client.AccountService.Delete(account.ID, 0)
 		if err != nil {
@@ -183,9 +244,189 @@ func TestAccountService_Delete(t *testing.T) {
 	})
 }
 
+func TestAccountService_Update(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	t.Run("When updating account then attributes are changed and version is incremented", func(t *testing.T) {
+		account, err := givenMinimalAccount(client)
+		if err != nil {
+			t.Fatalf("error while generating minimal account, %s", err.Error())
+		}
+
+		updated, err := client.AccountService.Update(AccountRequest{
+			ID:             account.ID,
+			OrganisationID: account.OrganisationID,
+			Version:        account.Version,
+			Attributes:     AccountAttributes{BankID: "400300"},
+		})
+		if err != nil {
+			t.Fatalf("update account returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: updated.Attributes.BankID, expected: "400300", name: "BankID"},
+			{actual: updated.Attributes.Country, expected: "GB", name: "Country"},
+			{actual: updated.Version, expected: account.Version + 1, name: "Version"},
+		})
+	})
+
+	t.Run("When updating account with stale version then error", func(t *testing.T) {
+		account, err := givenMinimalAccount(client)
+		if err != nil {
+			t.Fatalf("error while generating minimal account, %s", err.Error())
+		}
+
+		_, err = client.AccountService.Update(AccountRequest{ID: account.ID, Version: 2})
+
+		thenEquals(t, assertions{
+			{actual: IsConflict(err), expected: true, name: "IsConflict"},
+		})
+	})
+}
+
+func TestAccountService_FetchConcurrently(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	t.Run("When fetching the same account concurrently then a single request is sent", func(t *testing.T) {
+		id, orgID, err := generateIDs()
+		if err != nil {
+			t.Fatalf("error while generating IDs, %s", err.Error())
+		}
+
+		var calls int32
+		release := make(chan struct{})
+		server.HandleFunc(form3test.AccountsPath+"/"+id, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			form3test.WriteDocument(w, http.StatusOK, jsonapi.Document{Data: Account{ID: id, OrganisationID: orgID}})
+		})
+
+		const callers = 5
+		accounts := make(chan Account, callers)
+		for idx := 0; idx < callers; idx++ {
+			go func() {
+				account, _ := client.AccountService.Fetch(id)
+				accounts <- account
+			}()
+		}
+
+		for client.AccountService.fetches.waiting(id) < callers-1 {
+			runtime.Gosched()
+		}
+		close(release)
+
+		for idx := 0; idx < callers; idx++ {
+			thenEquals(t, assertions{
+				{actual: (<-accounts).ID, expected: id, name: fmt.Sprintf("Account%d.ID", idx)},
+			})
+		}
+		thenEquals(t, assertions{
+			{actual: atomic.LoadInt32(&calls), expected: int32(1), name: "Calls"},
+		})
+	})
+
+	t.Run("When updating account while it is fetched then later fetches return the update", func(t *testing.T) {
+		id, orgID, err := generateIDs()
+		if err != nil {
+			t.Fatalf("error while generating IDs, %s", err.Error())
+		}
+
+		var gets int32
+		started, release := make(chan struct{}), make(chan struct{})
+		server.HandleFunc(form3test.AccountsPath+"/"+id, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPatch {
+				form3test.WriteDocument(w, http.StatusOK, jsonapi.Document{Data: Account{ID: id, OrganisationID: orgID, Version: 1}})
+				return
+			}
+
+			if atomic.AddInt32(&gets, 1) == 1 {
+				close(started)
+				<-release
+				form3test.WriteDocument(w, http.StatusOK, jsonapi.Document{Data: Account{ID: id, OrganisationID: orgID}})
+				return
+			}
+			form3test.WriteDocument(w, http.StatusOK, jsonapi.Document{Data: Account{ID: id, OrganisationID: orgID, Version: 1}})
+		})
+
+		inFlight := make(chan Account, 1)
+		go func() {
+			account, _ := client.AccountService.Fetch(id)
+			inFlight <- account
+		}()
+		<-started
+
+		if _, err := client.AccountService.Update(AccountRequest{ID: id, Version: 0}); err != nil {
+			t.Fatalf("update account returned with error %v", err.Error())
+		}
+
+		afterUpdate := make(chan Account, 1)
+		go func() {
+			account, _ := client.AccountService.Fetch(id)
+			afterUpdate <- account
+		}()
+
+		var fetched Account
+		select {
+		case fetched = <-afterUpdate:
+			close(release)
+		case <-time.After(time.Second):
+			close(release)
+			fetched = <-afterUpdate
+		}
+
+		thenEquals(t, assertions{
+			{actual: fetched.Version, expected: 1, name: "Version"},
+			{actual: (<-inFlight).Version, expected: 0, name: "InFlight.Version"},
+			{actual: atomic.LoadInt32(&gets), expected: int32(2), name: "Gets"},
+		})
+	})
+
+	t.Run("When fetching many accounts then results are in input order", func(t *testing.T) {
+		var ids []string
+		for idx := 0; idx < 3; idx++ {
+			account, err := givenMinimalAccount(client)
+			if err != nil {
+				t.Fatalf("error while generating minimal account, %s", err.Error())
+			}
+			ids = append(ids, account.ID)
+		}
+
+		missing, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating UUID, %s", err.Error())
+		}
+		ids = append(ids[:1], missing, ids[1], ids[2])
+
+		results := client.AccountService.FetchMany(ids, 2)
+
+		thenEquals(t, assertions{
+			{actual: len(results), expected: 4, name: "Length"},
+			{actual: IsNotFound(results[1].Err), expected: true, name: "Missing.IsNotFound"},
+		})
+		for idx, result := range results {
+			thenEquals(t, assertions{
+				{actual: result.ID, expected: ids[idx], name: fmt.Sprintf("Result%d.ID", idx)},
+			})
+			if idx != 1 {
+				thenEquals(t, assertions{
+					{actual: result.Account.ID, expected: ids[idx], name: fmt.Sprintf("Result%d.Account.ID", idx)},
+					{actual: result.Err, expected: nil, name: fmt.Sprintf("Result%d.Err", idx)},
+				})
+			}
+		}
+	})
+}
+
 func TestAccountService_List(t *testing.T) {
-	client := NewClient(nil, baseURL)
+	client := givenAccountAPIClient(t)
 	clean(t, client)
+	t.Cleanup(func() { clean(t, client) })
 
 	t.Run("Given no accounts", func(t *testing.T) {
 		t.Run("When no accounts then list is empty", func(t *testing.T) {
@@ -224,6 +465,33 @@ func TestAccountService_List(t *testing.T) {
 	})
 }
 
+// givenAccountAPIClient returns a client of the account API. While recording or replaying, IDs generated by the test
+// are seeded by its name, so replayed requests match recorded ones whichever tests run.
+func givenAccountAPIClient(t *testing.T) *Client {
+	if cassetteMissing {
+		t.Skipf("%s has not been recorded, record it with FORM3_CASSETTE=record docker-compose up", accountsCassette)
+	}
+
+	if recorder != nil {
+		seed := fnv.New64a()
+		seed.Write([]byte(t.Name()))
+		randomSource = mathrand.New(mathrand.NewSource(int64(seed.Sum64())))
+		t.Cleanup(func() { randomSource = rand.Reader })
+	}
+
+	return NewClient(httpClient, baseURL)
+}
+
+// givenDeletedAfterTest deletes the account created by the test when it finishes, so IDs seeded by test names can be
+// recorded again against the same database.
+func givenDeletedAfterTest(t *testing.T, client *Client, id string) {
+	t.Cleanup(func() {
+		if err := client.AccountService.DeleteLatest(id); err != nil && !IsNotFound(err) {
+			t.Errorf("error while deleting account %s, %s", id, err.Error())
+		}
+	})
+}
+
 func whenListingAccountsWith(t *testing.T, client *Client, opts ListOptions) ([]Account, bool) {
 	list, hasNext, err := client.AccountService.List(opts)
 	if err != nil {
@@ -245,24 +513,15 @@ func givenAccounts(t *testing.T, client *Client, numberOfAccounts int) {
 }
 
 func clean(t *testing.T, client *Client) {
-	hasNext := true
-
-	for hasNext {
-		var list []Account
-		var err error
-
-		list, hasNext, err = client.AccountService.List(ListOptions{})
-		if err != nil {
-			t.Errorf("error while listing accounts, %s", err.Error())
-			t.FailNow()
-		}
+	summary, err := client.AccountService.DeleteWhere(AccountFilter{}, DeleteOptions{Confirm: true})
+	if err != nil {
+		t.Errorf("error while listing accounts, %s", err.Error())
+		t.FailNow()
+	}
 
-		for _, acc := range list {
-			if err := client.AccountService.Delete(acc.ID, acc.Version); err != nil {
-				t.Errorf("error while deleting account, %s", err.Error())
-				t.FailNow()
-			}
-		}
+	for _, err := range summary.Errors {
+		t.Errorf("error while deleting account, %s", err.Error())
+		t.FailNow()
 	}
 }
 
@@ -341,9 +600,21 @@ func generateUUIDElements(numberOfElements int) ([]interface{}, error) {
 
 func generateHex(length int) (string, error) {
 	bytes := make([]byte, length)
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := io.ReadFull(randomSource, bytes); err != nil {
 		return "", err
 	}
 
 	return hex.EncodeToString(bytes), nil
 }
+
+// waiting returns the number of callers waiting for the call with the key.
+func (g *flightGroup) waiting(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if call, ok := g.calls[key]; ok {
+		return call.dups
+	}
+
+	return 0
+}
@@ -0,0 +1,124 @@
+package form3
+
+import "encoding/json"
+
+const (
+	auditEntriesBasePath = "/v1/audit/entries"
+	auditPageSize        = 100
+)
+
+// AuditEntry describes a single change of a record.
+type AuditEntry struct {
+	ID             string               `json:"id,omitempty"`
+	OrganisationID string               `json:"organisation_id,omitempty"`
+	Type           string               `json:"type,omitempty"`
+	Version        int                  `json:"version,omitempty"`
+	Attributes     AuditEntryAttributes `json:"attributes,omitempty"`
+}
+
+// AuditEntryAttributes describes who changed a record, when and how.
+type AuditEntryAttributes struct {
+	ActorOrganisationID string `json:"actor_organisation_id,omitempty"`
+	ActorID             string `json:"actor_id,omitempty"`
+	ActorName           string `json:"actor_name,omitempty"`
+	// Action is a type of the change, e.g. EventTypeUpdated.
+	Action      string `json:"action,omitempty"`
+	ActionTime  string `json:"action_time,omitempty"`
+	Description string `json:"description,omitempty"`
+	RecordType  string `json:"record_type,omitempty"`
+	RecordID    string `json:"record_id,omitempty"`
+	// BeforeData is a snapshot of the record before the change. It is empty for created records.
+	BeforeData json.RawMessage `json:"before_data,omitempty"`
+	// AfterData is a snapshot of the record after the change. It is empty for deleted records.
+	AfterData json.RawMessage `json:"after_data,omitempty"`
+}
+
+// AccountChange is an audit entry of an account with snapshots decoded as accounts.
+type AccountChange struct {
+	AuditEntry
+	// Before is nil when the account has been created.
+	Before *Account
+	// After is nil when the account has been deleted.
+	After *Account
+}
+
+// DecodeBefore decodes the snapshot before the change into v. It returns false if there is no snapshot.
+func (e AuditEntry) DecodeBefore(v interface{}) (bool, error) {
+	return decodeSnapshot(e.Attributes.BeforeData, v)
+}
+
+// DecodeAfter decodes the snapshot after the change into v. It returns false if there is no snapshot.
+func (e AuditEntry) DecodeAfter(v interface{}) (bool, error) {
+	return decodeSnapshot(e.Attributes.AfterData, v)
+}
+
+type AuditService struct {
+	client *Client
+}
+
+// List audit entries of a record given by its type, e.g. RecordTypeAccounts, and ID. Accepts pagination options as an
+// argument. Returns list of entries, true if there are more pages with entries or an error for network problem, and
+// for non-2xx server statuses.
+func (a *AuditService) List(recordType, recordID string, options ListOptions) ([]AuditEntry, bool, error) {
+	var entries []AuditEntry
+	hasNext, err := a.client.listResources(resourcePath(auditEntriesBasePath, recordType, recordID), pagingQuery(options), &entries)
+
+	return entries, hasNext, err
+}
+
+// History returns all changes of an account in the order returned by the audit service. Returns an error for network
+// problem, for non-2xx server statuses, and for snapshots which are not accounts.
+func (a *AccountService) History(id string) ([]AccountChange, error) {
+	var changes []AccountChange
+
+	for page, hasNext := 0, true; hasNext; page++ {
+		var (
+			entries []AuditEntry
+			err     error
+		)
+
+		entries, hasNext, err = a.client.AuditService.List(RecordTypeAccounts, id, ListOptions{Page: page, PageSize: auditPageSize})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			change, err := newAccountChange(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+func newAccountChange(entry AuditEntry) (AccountChange, error) {
+	change := AccountChange{AuditEntry: entry}
+
+	before := Account{}
+	if ok, err := entry.DecodeBefore(&before); err != nil {
+		return AccountChange{}, err
+	} else if ok {
+		change.Before = &before
+	}
+
+	after := Account{}
+	if ok, err := entry.DecodeAfter(&after); err != nil {
+		return AccountChange{}, err
+	} else if ok {
+		change.After = &after
+	}
+
+	return change, nil
+}
+
+func decodeSnapshot(raw json.RawMessage, v interface{}) (bool, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(raw, v)
+}
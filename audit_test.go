@@ -0,0 +1,88 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestAuditService_List(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	t.Run("When organisation is updated then entry has before and after snapshots", func(t *testing.T) {
+		organisation, err := givenOrganisation(client, "Before")
+		if err != nil {
+			t.Fatalf("error while creating organisation, %s", err.Error())
+		}
+
+		_, err = client.OrganisationService.Update(OrganisationRequest{
+			ID:         organisation.ID,
+			Version:    organisation.Version,
+			Attributes: OrganisationAttributes{Name: "After"},
+		})
+		if err != nil {
+			t.Fatalf("update organisation returned with error %v", err.Error())
+		}
+
+		entries, hasNext, err := client.AuditService.List(organisationType, organisation.ID, ListOptions{})
+		if err != nil {
+			t.Fatalf("list audit entries returned with error %v", err.Error())
+		}
+
+		before, after := Organisation{}, Organisation{}
+		hasBefore, err := entries[1].DecodeBefore(&before)
+		if err != nil {
+			t.Fatalf("error while decoding snapshot, %s", err.Error())
+		}
+		hasAfter, err := entries[1].DecodeAfter(&after)
+		if err != nil {
+			t.Fatalf("error while decoding snapshot, %s", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: len(entries), expected: 2, name: "Length"},
+			{actual: hasNext, expected: false, name: "HasNext"},
+			{actual: entries[0].Attributes.Action, expected: EventTypeCreated, name: "CreatedAction"},
+			{actual: entries[1].Attributes.Action, expected: EventTypeUpdated, name: "UpdatedAction"},
+			{actual: hasBefore && hasAfter, expected: true, name: "HasSnapshots"},
+			{actual: before.Attributes.Name, expected: "Before", name: "Before.Name"},
+			{actual: after.Attributes.Name, expected: "After", name: "After.Name"},
+		})
+	})
+}
+
+func TestAccountService_History(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	t.Run("When account is created and deleted then history has both changes", func(t *testing.T) {
+		account, err := givenMinimalAccount(client)
+		if err != nil {
+			t.Fatalf("error while generating minimal account, %s", err.Error())
+		}
+
+		if err := client.AccountService.Delete(account.ID, account.Version); err != nil {
+			t.Fatalf("error while deleting account %s", err.Error())
+		}
+
+		history, err := client.AccountService.History(account.ID)
+		if err != nil {
+			t.Fatalf("account history returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: len(history), expected: 2, name: "Length"},
+			{actual: history[0].Attributes.Action, expected: EventTypeCreated, name: "CreatedAction"},
+			{actual: history[0].Before == nil, expected: true, name: "Created.Before"},
+			{actual: *history[0].After, expected: account, name: "Created.After"},
+			{actual: history[1].Attributes.Action, expected: EventTypeDeleted, name: "DeletedAction"},
+			{actual: *history[1].Before, expected: account, name: "Deleted.Before"},
+			{actual: history[1].After == nil, expected: true, name: "Deleted.After"},
+		})
+	})
+}
@@ -0,0 +1,274 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned, wrapped with the operation, for requests rejected by an open CircuitBreaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// idSegmentRegex matches path segments holding IDs, which are left out of operation names.
+var idSegmentRegex = regexp.MustCompile(`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9]+)$`)
+
+// CircuitState is a state of a circuit of a single operation.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through and counts consecutive failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects requests with ErrCircuitOpen until OpenTimeout passes.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial requests through. A success closes the circuit, a failure opens
+	// it again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitSettings configures circuits. Zero fields take defaults of the CircuitBreaker.
+type CircuitSettings struct {
+	// FailureThreshold is a number of consecutive failures which opens the circuit, 5 by default.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before trial requests are let through, 30 seconds by default.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is a number of concurrent trial requests in the half-open state, 1 by default.
+	HalfOpenRequests int
+}
+
+// CircuitBreaker fails requests fast while the Form3 API keeps failing. Every operation, i.e. a method and a path with
+// IDs replaced by {id} such as "GET /v1/organisation/accounts/{id}", has its own circuit. Network errors, 429 and 5xx
+// statuses count as failures. Set it as Client.CircuitBreaker; it is safe for concurrent use.
+type CircuitBreaker struct {
+	CircuitSettings
+	// Operations overrides settings of single operations.
+	Operations map[string]CircuitSettings
+	// OnStateChange is called whenever a circuit changes its state. It is called after the breaker is unlocked, so it
+	// may call State and States.
+	OnStateChange func(operation string, from, to CircuitState)
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	changes  []stateChange
+}
+
+// stateChange is a transition recorded under the lock and reported to OnStateChange after unlocking.
+type stateChange struct {
+	operation string
+	from, to  CircuitState
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+// State returns the state of the operation circuit. Operations which have not been called are closed.
+func (b *CircuitBreaker) State(operation string) CircuitState {
+	b.mu.Lock()
+	defer b.unlock()
+
+	c, ok := b.circuits[operation]
+	if !ok {
+		return CircuitClosed
+	}
+
+	b.refresh(operation, c)
+	return c.state
+}
+
+// States returns states of all called operations, e.g. to export them as metrics.
+func (b *CircuitBreaker) States() map[string]CircuitState {
+	b.mu.Lock()
+	defer b.unlock()
+
+	states := make(map[string]CircuitState, len(b.circuits))
+	for operation, c := range b.circuits {
+		b.refresh(operation, c)
+		states[operation] = c.state
+	}
+
+	return states
+}
+
+// allow returns an error wrapping ErrCircuitOpen if a request of the operation must not be sent.
+func (b *CircuitBreaker) allow(operation string) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	c := b.circuit(operation)
+	b.refresh(operation, c)
+
+	switch c.state {
+	case CircuitOpen:
+		return fmt.Errorf("%s: %w", operation, ErrCircuitOpen)
+	case CircuitHalfOpen:
+		if c.trials >= b.settings(operation).HalfOpenRequests {
+			return fmt.Errorf("%s: %w", operation, ErrCircuitOpen)
+		}
+		c.trials++
+	}
+
+	return nil
+}
+
+// record counts the outcome of a request allowed by allow.
+func (b *CircuitBreaker) record(operation string, failed bool) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	c := b.circuit(operation)
+	if c.state == CircuitHalfOpen && c.trials > 0 {
+		c.trials--
+	}
+
+	// Requests sent before the circuit opened must not close it.
+	if c.state == CircuitOpen {
+		return
+	}
+
+	switch {
+	case !failed:
+		c.failures = 0
+		b.transition(operation, c, CircuitClosed)
+	case c.state == CircuitHalfOpen:
+		b.open(operation, c)
+	case c.state == CircuitClosed:
+		c.failures++
+		if c.failures >= b.settings(operation).FailureThreshold {
+			b.open(operation, c)
+		}
+	}
+}
+
+func (b *CircuitBreaker) circuit(operation string) *circuit {
+	if b.circuits == nil {
+		b.circuits = map[string]*circuit{}
+	}
+
+	c, ok := b.circuits[operation]
+	if !ok {
+		c = &circuit{}
+		b.circuits[operation] = c
+	}
+
+	return c
+}
+
+// refresh moves an open circuit to half-open once its timeout has passed.
+func (b *CircuitBreaker) refresh(operation string, c *circuit) {
+	if c.state == CircuitOpen && !b.now().Before(c.openedAt.Add(b.settings(operation).OpenTimeout)) {
+		c.trials = 0
+		b.transition(operation, c, CircuitHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) open(operation string, c *circuit) {
+	c.openedAt = b.now()
+	c.failures = 0
+	b.transition(operation, c, CircuitOpen)
+}
+
+func (b *CircuitBreaker) transition(operation string, c *circuit, to CircuitState) {
+	from := c.state
+	if from == to {
+		return
+	}
+
+	c.state = to
+	if b.OnStateChange != nil {
+		b.changes = append(b.changes, stateChange{operation: operation, from: from, to: to})
+	}
+}
+
+// unlock unlocks the breaker and reports transitions recorded while it was locked.
+func (b *CircuitBreaker) unlock() {
+	changes := b.changes
+	b.changes = nil
+	b.mu.Unlock()
+
+	for _, change := range changes {
+		b.OnStateChange(change.operation, change.from, change.to)
+	}
+}
+
+func (b *CircuitBreaker) settings(operation string) CircuitSettings {
+	settings := b.CircuitSettings
+	if override, ok := b.Operations[operation]; ok {
+		if override.FailureThreshold > 0 {
+			settings.FailureThreshold = override.FailureThreshold
+		}
+		if override.OpenTimeout > 0 {
+			settings.OpenTimeout = override.OpenTimeout
+		}
+		if override.HalfOpenRequests > 0 {
+			settings.HalfOpenRequests = override.HalfOpenRequests
+		}
+	}
+
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = defaultFailureThreshold
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = defaultOpenTimeout
+	}
+	if settings.HalfOpenRequests <= 0 {
+		settings.HalfOpenRequests = 1
+	}
+
+	return settings
+}
+
+func (b *CircuitBreaker) now() time.Time {
+	if b.Now != nil {
+		return b.Now()
+	}
+
+	return time.Now()
+}
+
+// Operation returns the name of the circuit of a request: the method and the path with IDs replaced by {id}.
+func Operation(method, path string) string {
+	segments := strings.Split(path, "/")
+	for idx, segment := range segments {
+		if idSegmentRegex.MatchString(segment) {
+			segments[idx] = "{id}"
+		}
+	}
+
+	return method + " " + strings.Join(segments, "/")
+}
+
+// isFailure tells whether the outcome of a request counts against its circuit.
+func isFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
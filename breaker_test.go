@@ -0,0 +1,176 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ptrsd/form3/fault"
+	"github.com/ptrsd/form3/form3test"
+)
+
+const (
+	listAccounts = "GET /v1/organisation/accounts"
+	fetchAccount = "GET /v1/organisation/accounts/{id}"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	t.Run("When failures reach threshold then circuit opens and requests fail fast", func(t *testing.T) {
+		transport := &fault.Transport{Rules: []fault.Rule{{Fault: fault.Status(http.StatusServiceUnavailable, ""), Probability: 1}}}
+		breaker := &CircuitBreaker{CircuitSettings: CircuitSettings{FailureThreshold: 3}}
+		client := givenBreakerClient(server.URL, transport, breaker)
+
+		for idx := 0; idx < 3; idx++ {
+			_, _, err := client.AccountService.List(ListOptions{})
+			thenEquals(t, assertions{
+				{actual: errors.Is(err, ErrCircuitOpen), expected: false, name: fmt.Sprintf("Failure%d.ErrCircuitOpen", idx)},
+			})
+		}
+
+		_, _, err := client.AccountService.List(ListOptions{})
+		thenEquals(t, assertions{
+			{actual: errors.Is(err, ErrCircuitOpen), expected: true, name: "ErrCircuitOpen"},
+			{actual: err.Error(), expected: listAccounts + ": circuit breaker is open", name: "Message"},
+			{actual: transport.Calls(), expected: 3, name: "Calls"},
+			{actual: breaker.State(listAccounts), expected: CircuitOpen, name: "State"},
+		})
+	})
+
+	t.Run("When one operation fails then other operations are not affected", func(t *testing.T) {
+		transport := &fault.Transport{Rules: []fault.Rule{{
+			Fault:       fault.Reset(),
+			Probability: 1,
+			Match:       func(req *http.Request) bool { return req.URL.Path == "/v1/organisation/accounts" },
+		}}}
+		breaker := &CircuitBreaker{CircuitSettings: CircuitSettings{FailureThreshold: 1}}
+		client := givenBreakerClient(server.URL, transport, breaker)
+
+		_, _, err := client.AccountService.List(ListOptions{})
+		thenNotEmpty(t, []assertion{{actual: err, name: "ListError"}})
+
+		_, err = client.AccountService.Fetch("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+
+		thenEquals(t, assertions{
+			{actual: IsNotFound(err), expected: true, name: "Fetch.IsNotFound"},
+			{actual: breaker.States(), expected: map[string]CircuitState{listAccounts: CircuitOpen, fetchAccount: CircuitClosed}, name: "States"},
+		})
+	})
+
+	t.Run("When open timeout passes then trial request closes or reopens the circuit", func(t *testing.T) {
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		transport := &fault.Transport{Script: []fault.Fault{
+			fault.Status(http.StatusBadGateway, ""),
+			fault.Status(http.StatusTooManyRequests, ""),
+		}}
+		var changes []string
+		breaker := &CircuitBreaker{
+			CircuitSettings: CircuitSettings{FailureThreshold: 1, OpenTimeout: time.Minute},
+			OnStateChange: func(operation string, from, to CircuitState) {
+				changes = append(changes, fmt.Sprintf("%s: %s -> %s", operation, from, to))
+			},
+			Now: func() time.Time { return now },
+		}
+		client := givenBreakerClient(server.URL, transport, breaker)
+
+		_, _, _ = client.AccountService.List(ListOptions{})
+		now = now.Add(59 * time.Second)
+		_, _, err := client.AccountService.List(ListOptions{})
+		thenEquals(t, assertions{
+			{actual: errors.Is(err, ErrCircuitOpen), expected: true, name: "BeforeTimeout"},
+		})
+
+		now = now.Add(time.Second)
+		thenEquals(t, assertions{
+			{actual: breaker.State(listAccounts), expected: CircuitHalfOpen, name: "HalfOpen"},
+		})
+
+		_, _, err = client.AccountService.List(ListOptions{})
+		thenEquals(t, assertions{
+			{actual: err.Error(), expected: "429 Too Many Requests", name: "FailedTrial"},
+			{actual: breaker.State(listAccounts), expected: CircuitOpen, name: "Reopened"},
+		})
+
+		now = now.Add(time.Minute)
+		_, _, err = client.AccountService.List(ListOptions{})
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "SuccessfulTrial"},
+			{actual: breaker.State(listAccounts), expected: CircuitClosed, name: "Closed"},
+			{actual: changes, expected: []string{
+				listAccounts + ": closed -> open",
+				listAccounts + ": open -> half-open",
+				listAccounts + ": half-open -> open",
+				listAccounts + ": open -> half-open",
+				listAccounts + ": half-open -> closed",
+			}, name: "Changes"},
+		})
+	})
+
+	t.Run("When state change callback reads state then it sees the new state", func(t *testing.T) {
+		transport := &fault.Transport{Script: []fault.Fault{fault.Reset()}}
+		var states []CircuitState
+		breaker := &CircuitBreaker{CircuitSettings: CircuitSettings{FailureThreshold: 1}}
+		breaker.OnStateChange = func(operation string, from, to CircuitState) {
+			states = append(states, breaker.State(operation))
+		}
+		client := givenBreakerClient(server.URL, transport, breaker)
+
+		_, _, _ = client.AccountService.List(ListOptions{})
+
+		thenEquals(t, assertions{
+			{actual: states, expected: []CircuitState{CircuitOpen}, name: "States"},
+		})
+	})
+
+	t.Run("When successes interleave failures then circuit stays closed", func(t *testing.T) {
+		transport := &fault.Transport{Script: []fault.Fault{
+			fault.Reset(), {}, fault.Reset(), {}, fault.Reset(),
+		}}
+		breaker := &CircuitBreaker{CircuitSettings: CircuitSettings{FailureThreshold: 2}}
+		client := givenBreakerClient(server.URL, transport, breaker)
+
+		for idx := 0; idx < 5; idx++ {
+			_, _, _ = client.AccountService.List(ListOptions{})
+		}
+
+		thenEquals(t, assertions{
+			{actual: breaker.State(listAccounts), expected: CircuitClosed, name: "State"},
+		})
+	})
+
+	t.Run("When operation has overrides then its settings are used", func(t *testing.T) {
+		transport := &fault.Transport{Rules: []fault.Rule{{Fault: fault.Reset(), Probability: 1}}}
+		breaker := &CircuitBreaker{
+			CircuitSettings: CircuitSettings{FailureThreshold: 5},
+			Operations:      map[string]CircuitSettings{fetchAccount: {FailureThreshold: 1}},
+		}
+		client := givenBreakerClient(server.URL, transport, breaker)
+
+		_, _, _ = client.AccountService.List(ListOptions{})
+		_, _ = client.AccountService.Fetch("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+
+		thenEquals(t, assertions{
+			{actual: breaker.State(listAccounts), expected: CircuitClosed, name: "Default"},
+			{actual: breaker.State(fetchAccount), expected: CircuitOpen, name: "Override"},
+		})
+	})
+}
+
+func TestOperation(t *testing.T) {
+	thenEquals(t, assertions{
+		{actual: Operation(http.MethodGet, "/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"), expected: fetchAccount, name: "UUID"},
+		{actual: Operation(http.MethodDelete, "/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc/events/12"), expected: "DELETE /v1/organisation/accounts/{id}/events/{id}", name: "Nested"},
+		{actual: Operation(http.MethodPost, "/v1/organisation/accounts"), expected: "POST /v1/organisation/accounts", name: "Collection"},
+	})
+}
+
+func givenBreakerClient(baseURL string, transport http.RoundTripper, breaker *CircuitBreaker) *Client {
+	client := NewClient(&http.Client{Transport: transport}, baseURL)
+	client.CircuitBreaker = breaker
+
+	return client
+}
@@ -0,0 +1,147 @@
+// Package bulk moves accounts between the Form3 API and CSV or NDJSON files.
+package bulk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ptrsd/form3"
+)
+
+// NameSeparator separates alternative bank account names flattened into a single CSV column.
+const NameSeparator = "|"
+
+// Format is a format of account files.
+type Format string
+
+const (
+	// FormatCSV is a comma separated file with a header row naming columns.
+	FormatCSV Format = "csv"
+	// FormatNDJSON is a newline delimited JSON file with one account per line.
+	FormatNDJSON Format = "ndjson"
+)
+
+// column reads and writes a single field of an account. Read-only columns, e.g. version, have no setter.
+type column struct {
+	get func(form3.Account) string
+	set func(*form3.AccountRequest, string) error
+}
+
+// Columns lists names of all supported CSV columns in their default order. Names match JSON names of account fields.
+var Columns = []string{
+	"id",
+	"organisation_id",
+	"version",
+	"created_on",
+	"modified_on",
+	"country",
+	"base_currency",
+	"bank_id",
+	"bank_id_code",
+	"bic",
+	"account_number",
+	"iban",
+	"bank_account_name",
+	"alternative_bank_account_names",
+	"account_classification",
+	"account_matching_opt_out",
+	"joint_account",
+	"first_name",
+	"title",
+	"secondary_identification",
+}
+
+var columns = map[string]column{
+	"id": {
+		get: func(a form3.Account) string { return a.ID },
+		set: func(r *form3.AccountRequest, v string) error { r.ID = v; return nil },
+	},
+	"organisation_id": {
+		get: func(a form3.Account) string { return a.OrganisationID },
+		set: func(r *form3.AccountRequest, v string) error { r.OrganisationID = v; return nil },
+	},
+	"version": {
+		get: func(a form3.Account) string { return strconv.Itoa(a.Version) },
+	},
+	"created_on": {
+		get: func(a form3.Account) string { return a.CreatedOn },
+	},
+	"modified_on": {
+		get: func(a form3.Account) string { return a.ModifiedOn },
+	},
+	"country":                  stringAttribute(func(a *form3.AccountAttributes) *string { return &a.Country }),
+	"base_currency":            stringAttribute(func(a *form3.AccountAttributes) *string { return &a.BaseCurrency }),
+	"bank_id":                  stringAttribute(func(a *form3.AccountAttributes) *string { return &a.BankID }),
+	"bank_id_code":             stringAttribute(func(a *form3.AccountAttributes) *string { return &a.BankIDCode }),
+	"bic":                      stringAttribute(func(a *form3.AccountAttributes) *string { return &a.Bic }),
+	"account_number":           stringAttribute(func(a *form3.AccountAttributes) *string { return &a.AccountNumber }),
+	"iban":                     stringAttribute(func(a *form3.AccountAttributes) *string { return &a.Iban }),
+	"bank_account_name":        stringAttribute(func(a *form3.AccountAttributes) *string { return &a.BankAccountName }),
+	"account_classification":   stringAttribute(func(a *form3.AccountAttributes) *string { return &a.AccountClassification }),
+	"first_name":               stringAttribute(func(a *form3.AccountAttributes) *string { return &a.FirstName }),
+	"title":                    stringAttribute(func(a *form3.AccountAttributes) *string { return &a.Title }),
+	"secondary_identification": stringAttribute(func(a *form3.AccountAttributes) *string { return &a.SecondaryIdentification }),
+	"account_matching_opt_out": boolAttribute(func(a *form3.AccountAttributes) *bool { return &a.AccountMatchingOptOut }),
+	"joint_account":            boolAttribute(func(a *form3.AccountAttributes) *bool { return &a.JointAccount }),
+	"alternative_bank_account_names": {
+		get: func(a form3.Account) string {
+			return strings.Join(a.Attributes.AlternativeBankAccountNames, NameSeparator)
+		},
+		set: func(r *form3.AccountRequest, v string) error {
+			r.Attributes.AlternativeBankAccountNames = nil
+			if v != "" {
+				r.Attributes.AlternativeBankAccountNames = strings.Split(v, NameSeparator)
+			}
+			return nil
+		},
+	},
+}
+
+// ValidateColumns returns an error if any of names is not a supported column.
+func ValidateColumns(names []string) error {
+	for _, name := range names {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("unknown column %q", name)
+		}
+	}
+
+	return nil
+}
+
+// Value returns the value of the named column of the account as written to CSV files.
+func Value(account form3.Account, name string) string {
+	c, ok := columns[name]
+	if !ok {
+		return ""
+	}
+
+	return c.get(account)
+}
+
+func stringAttribute(field func(*form3.AccountAttributes) *string) column {
+	return column{
+		get: func(a form3.Account) string { return *field(&a.Attributes) },
+		set: func(r *form3.AccountRequest, v string) error { *field(&r.Attributes) = v; return nil },
+	}
+}
+
+func boolAttribute(field func(*form3.AccountAttributes) *bool) column {
+	return column{
+		get: func(a form3.Account) string { return strconv.FormatBool(*field(&a.Attributes)) },
+		set: func(r *form3.AccountRequest, v string) error {
+			if v == "" {
+				*field(&r.Attributes) = false
+				return nil
+			}
+
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", v)
+			}
+
+			*field(&r.Attributes) = parsed
+			return nil
+		},
+	}
+}
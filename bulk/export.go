@@ -0,0 +1,112 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ptrsd/form3"
+)
+
+// AccountLister lists accounts matching a filter page by page, it is implemented by form3.AccountService.
+type AccountLister interface {
+	ListWhere(options form3.ListOptions, filter form3.AccountFilter) ([]form3.Account, bool, error)
+}
+
+// Exporter writes all accounts to CSV or NDJSON files.
+type Exporter struct {
+	// Service lists accounts, usually a form3.Client's AccountService.
+	Service AccountLister
+	// Columns of CSV files, Columns if not set. Ignored for NDJSON files, which hold whole accounts.
+	Columns []string
+	// PageSize is a size of listed pages, the server default if not set.
+	PageSize int
+	// OrganisationID limits the export to accounts of a single organisation if set.
+	OrganisationID string
+}
+
+// Export lists all accounts and writes them to w sorted by ID, so that exports taken at different times can be diffed.
+// It returns the number of written accounts.
+func (e *Exporter) Export(w io.Writer, format Format) (int, error) {
+	names := e.Columns
+	if len(names) == 0 {
+		names = Columns
+	}
+
+	if err := ValidateColumns(names); err != nil {
+		return 0, err
+	}
+
+	var write func(io.Writer, []form3.Account) error
+	switch format {
+	case FormatCSV:
+		write = func(w io.Writer, accounts []form3.Account) error { return writeCSV(w, names, accounts) }
+	case FormatNDJSON:
+		write = writeNDJSON
+	default:
+		return 0, fmt.Errorf("unsupported format %q", format)
+	}
+
+	accounts, err := listAll(e.Service, e.PageSize, e.OrganisationID)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(accounts), write(w, accounts)
+}
+
+// listAll walks all pages and returns accounts of the organisation, or all accounts if organisationID is empty,
+// sorted by ID.
+func listAll(service AccountLister, pageSize int, organisationID string) ([]form3.Account, error) {
+	var accounts []form3.Account
+	for page, hasNext := 0, true; hasNext; page++ {
+		var (
+			list []form3.Account
+			err  error
+		)
+
+		options := form3.ListOptions{Page: page, PageSize: pageSize}
+		list, hasNext, err = service.ListWhere(options, form3.AccountFilter{OrganisationID: organisationID})
+		if err != nil {
+			return nil, fmt.Errorf("error while listing page %d, %w", page, err)
+		}
+
+		accounts = append(accounts, list...)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	return accounts, nil
+}
+
+func writeCSV(w io.Writer, names []string, accounts []form3.Account) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(names); err != nil {
+		return err
+	}
+
+	record := make([]string, len(names))
+	for _, account := range accounts {
+		for idx, name := range names {
+			record[idx] = Value(account, name)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeNDJSON(w io.Writer, accounts []form3.Account) error {
+	encoder := json.NewEncoder(w)
+	for _, account := range accounts {
+		if err := encoder.Encode(account); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
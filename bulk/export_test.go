@@ -0,0 +1,99 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ptrsd/form3"
+	"github.com/ptrsd/form3/form3test"
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+const otherOrganisationID = "5b438472-e8f7-4ce5-a189-2968e6f8f62e"
+
+func TestExporter_Export(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	givenAccount(server, firstAccountID, testOrganisationID, `{"country":"FR","joint_account":true}`)
+	givenAccount(server, secondAccountID, testOrganisationID, `{"country":"GB","alternative_bank_account_names":["Jane","J, Doe"]}`)
+	givenAccount(server, thirdAccountID, otherOrganisationID, `{"country":"GB"}`)
+
+	exporter := Exporter{
+		Service:        form3.NewClient(nil, server.URL).AccountService,
+		Columns:        []string{"id", "country", "alternative_bank_account_names", "joint_account"},
+		PageSize:       1,
+		OrganisationID: testOrganisationID,
+	}
+
+	t.Run("When exporting CSV then accounts of the organisation are written sorted by ID", func(t *testing.T) {
+		out := bytes.Buffer{}
+		count, err := exporter.Export(&out, FormatCSV)
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Count", 2, count)
+		thenEqual(t, "Output", "id,country,alternative_bank_account_names,joint_account\n"+
+			secondAccountID+`,GB,"Jane|J, Doe",false`+"\n"+
+			firstAccountID+",FR,,true\n", out.String())
+	})
+
+	t.Run("When exporting NDJSON then whole accounts are written", func(t *testing.T) {
+		out := bytes.Buffer{}
+		count, err := exporter.Export(&out, FormatNDJSON)
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Count", 2, count)
+
+		decoder := json.NewDecoder(&out)
+		var ids []string
+		for decoder.More() {
+			account := form3.Account{}
+			if err := decoder.Decode(&account); err != nil {
+				t.Fatalf("error while decoding output, %s", err.Error())
+			}
+			ids = append(ids, account.ID)
+		}
+		thenEqual(t, "IDs", []string{secondAccountID, firstAccountID}, ids)
+	})
+
+	t.Run("When exporting accounts of organisation then server filters them", func(t *testing.T) {
+		lister := &filterRecorder{AccountLister: exporter.Service}
+		exporter := Exporter{Service: lister, PageSize: 1, OrganisationID: testOrganisationID}
+
+		_, err := exporter.Export(&bytes.Buffer{}, FormatCSV)
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Filters", []form3.AccountFilter{
+			{OrganisationID: testOrganisationID},
+			{OrganisationID: testOrganisationID},
+		}, lister.filters)
+	})
+
+	t.Run("When column is unknown then error is returned", func(t *testing.T) {
+		exporter := Exporter{Service: exporter.Service, Columns: []string{"colour"}}
+
+		_, err := exporter.Export(&bytes.Buffer{}, FormatCSV)
+
+		thenEqual(t, "Error", `unknown column "colour"`, errorMessage(err))
+	})
+}
+
+// filterRecorder records filters of listed pages.
+type filterRecorder struct {
+	AccountLister
+	filters []form3.AccountFilter
+}
+
+func (r *filterRecorder) ListWhere(options form3.ListOptions, filter form3.AccountFilter) ([]form3.Account, bool, error) {
+	r.filters = append(r.filters, filter)
+	return r.AccountLister.ListWhere(options, filter)
+}
+
+func givenAccount(server *form3test.Server, id, organisationID, attributes string) {
+	server.Put(form3test.AccountsPath, jsonapi.Resource{
+		ID:             id,
+		Type:           "accounts",
+		OrganisationID: organisationID,
+		Attributes:     json.RawMessage(attributes),
+	})
+}
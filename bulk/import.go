@@ -0,0 +1,341 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/ptrsd/form3"
+)
+
+// Status is an outcome of importing a single row.
+type Status string
+
+const (
+	// StatusCreated means the account has been created.
+	StatusCreated Status = "created"
+	// StatusExists means an account with the same ID already exists.
+	StatusExists Status = "exists"
+	// StatusSkipped means the row has been imported by a previous run.
+	StatusSkipped Status = "skipped"
+	// StatusInvalid means the row failed validation and has not been sent.
+	StatusInvalid Status = "invalid"
+	// StatusFailed means the server rejected the account or could not be reached.
+	StatusFailed Status = "failed"
+)
+
+// reportHeader names columns of import reports.
+var reportHeader = []string{"row", "id", "status", "error"}
+
+var (
+	uuidRegex    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	countryRegex = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// AccountCreator creates accounts, it is implemented by form3.AccountService.
+type AccountCreator interface {
+	Create(createReq form3.AccountRequest) (form3.Account, error)
+}
+
+// Importer creates accounts read from CSV or NDJSON files.
+type Importer struct {
+	// Service creates accounts, usually a form3.Client's AccountService.
+	Service AccountCreator
+	// Workers is a number of concurrent Create calls, 1 if not set.
+	Workers int
+	// Mapping maps CSV header names to column names listed in Columns. Headers which are not mapped must already be
+	// column names. A header mapped to an empty string is ignored. Mapping does not apply to NDJSON files.
+	Mapping map[string]string
+	// OrganisationID is used for rows which do not have an organisation ID.
+	OrganisationID string
+	// Done holds IDs imported by a previous run, see ReadReport. Rows with these IDs are skipped.
+	Done map[string]bool
+}
+
+// Result is an outcome of importing a single row, as written to the report.
+type Result struct {
+	// Row is a 1-based number of the record in the file, not counting the CSV header.
+	Row    int
+	ID     string
+	Status Status
+	Err    string
+}
+
+// Summary counts rows by their status.
+type Summary struct {
+	Total    int
+	Created  int
+	Existing int
+	Skipped  int
+	Invalid  int
+	Failed   int
+}
+
+func (s *Summary) add(result Result) {
+	s.Total++
+	switch result.Status {
+	case StatusCreated:
+		s.Created++
+	case StatusExists:
+		s.Existing++
+	case StatusSkipped:
+		s.Skipped++
+	case StatusInvalid:
+		s.Invalid++
+	case StatusFailed:
+		s.Failed++
+	}
+}
+
+// row is a single parsed record of an import file.
+type row struct {
+	number  int
+	request form3.AccountRequest
+	err     error
+}
+
+// Import reads accounts from r and creates them, writing a CSV report with a result of every row to report as soon
+// as it is known, so the report can be used to resume an interrupted import. Rows are reported in completion order.
+// Invalid and failed rows are counted in Summary; an error is returned only if the file or the report cannot be
+// processed.
+func (i *Importer) Import(r io.Reader, format Format, report io.Writer) (Summary, error) {
+	read, err := rowReader(format, i.Mapping, i.OrganisationID)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	workers := i.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	rows := make(chan row)
+	results := make(chan Result)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range rows {
+				results <- i.importRow(r)
+			}
+		}()
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- read(r, rows)
+		close(rows)
+		wg.Wait()
+		close(results)
+	}()
+
+	summary, err := writeReport(report, results)
+	if rerr := <-readErr; rerr != nil {
+		return summary, rerr
+	}
+
+	return summary, err
+}
+
+func (i *Importer) importRow(r row) Result {
+	result := Result{Row: r.number, ID: r.request.ID}
+
+	if r.err == nil {
+		r.err = validate(r.request)
+	}
+
+	switch {
+	case r.err != nil:
+		result.Status, result.Err = StatusInvalid, r.err.Error()
+	case i.Done[r.request.ID]:
+		result.Status = StatusSkipped
+	default:
+		_, err := i.Service.Create(r.request)
+		switch {
+		case err == nil:
+			result.Status = StatusCreated
+		case form3.IsConflict(err):
+			result.Status = StatusExists
+		default:
+			result.Status, result.Err = StatusFailed, err.Error()
+		}
+	}
+
+	return result
+}
+
+// rowReader returns a function which reads rows of the format and sends them to rows. Mapping applies to CSV headers
+// and organisationID to rows which do not have one.
+func rowReader(format Format, mapping map[string]string, organisationID string) (func(io.Reader, chan<- row) error, error) {
+	switch format {
+	case FormatCSV:
+		return func(r io.Reader, rows chan<- row) error { return readCSV(r, mapping, organisationID, rows) }, nil
+	case FormatNDJSON:
+		return func(r io.Reader, rows chan<- row) error { return readNDJSON(r, organisationID, rows) }, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func readCSV(r io.Reader, mapping map[string]string, organisationID string, rows chan<- row) error {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("error while reading CSV header, %w", err)
+	}
+
+	names := make([]string, len(header))
+	for idx, name := range header {
+		if mapped, ok := mapping[name]; ok {
+			name = mapped
+		}
+		if name != "" {
+			if err := ValidateColumns([]string{name}); err != nil {
+				return err
+			}
+		}
+		names[idx] = name
+	}
+
+	for number := 1; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		r := row{number: number}
+		var parseErr *csv.ParseError
+		switch {
+		case errors.As(err, &parseErr):
+			r.err = parseErr.Err
+		case err != nil:
+			return err
+		default:
+			r.err = setColumns(&r.request, names, record)
+			if r.request.OrganisationID == "" {
+				r.request.OrganisationID = organisationID
+			}
+		}
+
+		rows <- r
+	}
+}
+
+func readNDJSON(r io.Reader, organisationID string, rows chan<- row) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for number := 0; scanner.Scan(); {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		number++
+		r := row{number: number}
+		if err := json.Unmarshal(line, &r.request); err != nil {
+			r.err = fmt.Errorf("invalid JSON, %w", err)
+		}
+		if r.request.OrganisationID == "" {
+			r.request.OrganisationID = organisationID
+		}
+
+		rows <- r
+	}
+
+	return scanner.Err()
+}
+
+func setColumns(request *form3.AccountRequest, names, record []string) error {
+	for idx, value := range record {
+		if idx >= len(names) || names[idx] == "" {
+			continue
+		}
+
+		c := columns[names[idx]]
+		if c.set == nil {
+			continue
+		}
+
+		if err := c.set(request, value); err != nil {
+			return fmt.Errorf("%s: %w", names[idx], err)
+		}
+	}
+
+	return nil
+}
+
+func validate(request form3.AccountRequest) error {
+	switch {
+	case !uuidRegex.MatchString(request.ID):
+		return fmt.Errorf("id: %q is not a UUID", request.ID)
+	case !uuidRegex.MatchString(request.OrganisationID):
+		return fmt.Errorf("organisation_id: %q is not a UUID", request.OrganisationID)
+	case !countryRegex.MatchString(request.Attributes.Country):
+		return fmt.Errorf("country: %q is not an ISO 3166-1 code", request.Attributes.Country)
+	}
+
+	return nil
+}
+
+func writeReport(w io.Writer, results <-chan Result) (Summary, error) {
+	summary := Summary{}
+	writer := csv.NewWriter(w)
+
+	var err error
+	write := func(record []string) {
+		if err != nil {
+			return
+		}
+		if err = writer.Write(record); err == nil {
+			writer.Flush()
+			err = writer.Error()
+		}
+	}
+
+	write(reportHeader)
+	for result := range results {
+		summary.add(result)
+		write([]string{strconv.Itoa(result.Row), result.ID, string(result.Status), result.Err})
+	}
+
+	return summary, err
+}
+
+// ReadReport reads a report written by Import and returns IDs of accounts which do not need to be imported again,
+// i.e. created, existing and skipped ones. The result can be used as Importer.Done to resume an import.
+func ReadReport(r io.Reader) (map[string]bool, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(reportHeader)
+
+	done := map[string]bool{}
+	if _, err := reader.Read(); err != nil {
+		if err == io.EOF {
+			return done, nil
+		}
+		return nil, fmt.Errorf("error while reading report header, %w", err)
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return done, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch Status(record[2]) {
+		case StatusCreated, StatusExists, StatusSkipped:
+			done[record[1]] = true
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package bulk
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ptrsd/form3"
+	"github.com/ptrsd/form3/form3test"
+)
+
+const (
+	testOrganisationID = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+	firstAccountID     = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+	secondAccountID    = "7b8d1a6e-0e0b-4a4c-9d3b-1f2e3d4c5b6a"
+	thirdAccountID     = "0f4b1d2c-3e5a-4b6c-8d7e-9f0a1b2c3d4e"
+)
+
+func TestImporter_Import(t *testing.T) {
+	t.Run("When importing CSV then valid rows are created and invalid ones reported", func(t *testing.T) {
+		server := form3test.NewServer()
+		defer server.Close()
+
+		input := "account_id,country,name,alternative_bank_account_names,joint_account,legacy\n" +
+			firstAccountID + ",GB,Jane Doe,Jane|J Doe,true,x\n" +
+			secondAccountID + ",gb,John Doe,,false,y\n" +
+			"not-a-uuid,GB,Jim Doe,,false,z\n"
+
+		importer := Importer{
+			Service:        form3.NewClient(nil, server.URL).AccountService,
+			Workers:        4,
+			Mapping:        map[string]string{"account_id": "id", "name": "bank_account_name", "legacy": ""},
+			OrganisationID: testOrganisationID,
+		}
+
+		report := bytes.Buffer{}
+		summary, err := importer.Import(strings.NewReader(input), FormatCSV, &report)
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Summary", Summary{Total: 3, Created: 1, Invalid: 2}, summary)
+
+		account, err := form3.NewClient(nil, server.URL).AccountService.Fetch(firstAccountID)
+		thenEqual(t, "FetchError", nil, err)
+		thenEqual(t, "OrganisationID", testOrganisationID, account.OrganisationID)
+		thenEqual(t, "BankAccountName", "Jane Doe", account.Attributes.BankAccountName)
+		thenEqual(t, "AlternativeBankAccountNames", []string{"Jane", "J Doe"}, account.Attributes.AlternativeBankAccountNames)
+		thenEqual(t, "JointAccount", true, account.Attributes.JointAccount)
+
+		thenEqual(t, "Report", []string{
+			"1," + firstAccountID + ",created,",
+			`2,` + secondAccountID + `,invalid,"country: ""gb"" is not an ISO 3166-1 code"`,
+			`3,not-a-uuid,invalid,"id: ""not-a-uuid"" is not a UUID"`,
+		}, reportLines(report.String()))
+	})
+
+	t.Run("When importing NDJSON with existing account then it is reported as existing", func(t *testing.T) {
+		server := form3test.NewServer()
+		defer server.Close()
+
+		input := `{"id":"` + firstAccountID + `","organisation_id":"` + testOrganisationID + `","attributes":{"country":"GB"}}` + "\n" +
+			"\n" +
+			`{"id":"` + firstAccountID + `","attributes":{"country":"GB"}}` + "\n" +
+			`{"id":` + "\n"
+
+		importer := Importer{Service: form3.NewClient(nil, server.URL).AccountService, OrganisationID: testOrganisationID}
+
+		summary, err := importer.Import(strings.NewReader(input), FormatNDJSON, &bytes.Buffer{})
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Summary", Summary{Total: 3, Created: 1, Existing: 1, Invalid: 1}, summary)
+	})
+
+	t.Run("When CSV has unknown column then error is returned", func(t *testing.T) {
+		importer := Importer{Service: &failingCreator{}}
+
+		_, err := importer.Import(strings.NewReader("id,colour\n"), FormatCSV, &bytes.Buffer{})
+
+		thenEqual(t, "Error", `unknown column "colour"`, errorMessage(err))
+	})
+
+	t.Run("When resuming from report then imported rows are skipped and failed ones retried", func(t *testing.T) {
+		input := "id,organisation_id,country\n" +
+			firstAccountID + "," + testOrganisationID + ",GB\n" +
+			secondAccountID + "," + testOrganisationID + ",GB\n" +
+			thirdAccountID + "," + testOrganisationID + ",GB\n"
+
+		creator := &failingCreator{failures: map[string]bool{secondAccountID: true}}
+		importer := Importer{Service: creator, Workers: 2}
+
+		report := bytes.Buffer{}
+		summary, err := importer.Import(strings.NewReader(input), FormatCSV, &report)
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Summary", Summary{Total: 3, Created: 2, Failed: 1}, summary)
+
+		done, err := ReadReport(&report)
+		thenEqual(t, "ReadReportError", nil, err)
+		thenEqual(t, "Done", map[string]bool{firstAccountID: true, thirdAccountID: true}, done)
+
+		creator.failures = nil
+		importer.Done = done
+		summary, err = importer.Import(strings.NewReader(input), FormatCSV, &bytes.Buffer{})
+		thenEqual(t, "ResumedError", nil, err)
+		thenEqual(t, "ResumedSummary", Summary{Total: 3, Created: 1, Skipped: 2}, summary)
+		thenEqual(t, "Created", []string{thirdAccountID, secondAccountID, firstAccountID}, creator.sortedCreated())
+	})
+}
+
+// failingCreator creates accounts in memory and fails for selected IDs.
+type failingCreator struct {
+	mu       sync.Mutex
+	failures map[string]bool
+	created  []string
+}
+
+func (c *failingCreator) Create(createReq form3.AccountRequest) (form3.Account, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failures[createReq.ID] {
+		return form3.Account{}, errors.New("connection reset")
+	}
+
+	c.created = append(c.created, createReq.ID)
+	return form3.Account{ID: createReq.ID}, nil
+}
+
+func (c *failingCreator) sortedCreated() []string {
+	created := append([]string{}, c.created...)
+	sort.Strings(created)
+	return created
+}
+
+// reportLines returns report rows without the header, sorted by row number.
+func reportLines(report string) []string {
+	lines := strings.Split(strings.TrimSpace(report), "\n")[1:]
+	sort.Strings(lines)
+	return lines
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func thenEqual(t *testing.T, name string, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s:\nExpected: %#v\n  Actual: %#v", name, expected, actual)
+	}
+}
@@ -0,0 +1,253 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ptrsd/form3"
+)
+
+// Kind is a kind of difference between a ledger and the API.
+type Kind string
+
+const (
+	// KindMissing means the account is in the ledger but not in the API.
+	KindMissing Kind = "missing"
+	// KindExtra means the account is in the API but not in the ledger.
+	KindExtra Kind = "extra"
+	// KindMismatch means the account is in both, but some of its fields differ.
+	KindMismatch Kind = "mismatch"
+)
+
+// differencesHeader names columns of reconciliation reports.
+var differencesHeader = []string{"id", "kind", "field", "ledger", "api", "result"}
+
+// AccountManager creates, lists and deletes accounts, it is implemented by form3.AccountService.
+type AccountManager interface {
+	AccountCreator
+	AccountLister
+	Delete(id string, version int) error
+}
+
+// Reconciler compares a ledger of accounts with accounts registered in the API.
+type Reconciler struct {
+	// Service manages accounts, usually a form3.Client's AccountService.
+	Service AccountManager
+	// Columns are compared for accounts found in both the ledger and the API. All importable columns but id by default.
+	Columns []string
+	// PageSize is a size of listed pages, the server default if not set.
+	PageSize int
+	// OrganisationID limits reconciliation to accounts of a single organisation if set. It is also used for ledger
+	// entries which do not have an organisation ID.
+	OrganisationID string
+}
+
+// FieldMismatch is a column which differs between the ledger and the API.
+type FieldMismatch struct {
+	Field  string
+	Ledger string
+	API    string
+}
+
+// Difference is an account which differs between the ledger and the API.
+type Difference struct {
+	ID   string
+	Kind Kind
+	// Ledger is the ledger entry of missing and mismatched accounts.
+	Ledger *form3.AccountRequest
+	// Account is the API account of extra and mismatched accounts.
+	Account *form3.Account
+	// Mismatches lists differing columns of mismatched accounts.
+	Mismatches []FieldMismatch
+	// Applied is true if Apply has created or deleted the account.
+	Applied bool
+	// Err is an error returned by Apply.
+	Err error
+}
+
+// ReadLedger reads ledger entries in the format of Importer files. Unlike Import, it fails if any row is invalid,
+// reporting the first one.
+func ReadLedger(r io.Reader, format Format, mapping map[string]string, organisationID string) ([]form3.AccountRequest, error) {
+	read, err := rowReader(format, mapping, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make(chan row)
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- read(r, rows)
+		close(rows)
+	}()
+
+	var (
+		ledger   []form3.AccountRequest
+		firstErr error
+	)
+	for r := range rows {
+		if r.err == nil {
+			r.err = validate(r.request)
+		}
+		if r.err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("row %d: %w", r.number, r.err)
+		}
+		ledger = append(ledger, r.request)
+	}
+
+	if err := <-readErr; err != nil {
+		return nil, err
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return ledger, nil
+}
+
+// Reconcile lists all accounts and compares them with the ledger. Differences are sorted by ID.
+func (rc *Reconciler) Reconcile(ledger []form3.AccountRequest) ([]Difference, error) {
+	names := rc.Columns
+	if len(names) == 0 {
+		names = comparedColumns()
+	}
+
+	if err := ValidateColumns(names); err != nil {
+		return nil, err
+	}
+
+	accounts, err := listAll(rc.Service, rc.PageSize, rc.OrganisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make(map[string]form3.Account, len(accounts))
+	for _, account := range accounts {
+		registered[account.ID] = account
+	}
+
+	var differences []Difference
+	recorded := make(map[string]bool, len(ledger))
+	for _, entry := range ledger {
+		entry := entry
+		if entry.OrganisationID == "" {
+			entry.OrganisationID = rc.OrganisationID
+		}
+		if rc.OrganisationID != "" && entry.OrganisationID != rc.OrganisationID {
+			continue
+		}
+		recorded[entry.ID] = true
+
+		account, ok := registered[entry.ID]
+		if !ok {
+			differences = append(differences, Difference{ID: entry.ID, Kind: KindMissing, Ledger: &entry})
+			continue
+		}
+
+		if mismatches := compare(entry, account, names); len(mismatches) > 0 {
+			differences = append(differences, Difference{
+				ID:         entry.ID,
+				Kind:       KindMismatch,
+				Ledger:     &entry,
+				Account:    &account,
+				Mismatches: mismatches,
+			})
+		}
+	}
+
+	for idx := range accounts {
+		if !recorded[accounts[idx].ID] {
+			differences = append(differences, Difference{ID: accounts[idx].ID, Kind: KindExtra, Account: &accounts[idx]})
+		}
+	}
+
+	sort.SliceStable(differences, func(i, j int) bool { return differences[i].ID < differences[j].ID })
+	return differences, nil
+}
+
+// Apply creates missing accounts and deletes extra ones, recording the outcome in each difference. Mismatched
+// accounts are left as they are and only reported, as they cannot be brought to the ledger without replacing them.
+// It returns the number of applied changes.
+func (rc *Reconciler) Apply(differences []Difference) int {
+	applied := 0
+	for idx := range differences {
+		difference := &differences[idx]
+
+		switch difference.Kind {
+		case KindMissing:
+			_, difference.Err = rc.Service.Create(*difference.Ledger)
+		case KindExtra:
+			difference.Err = rc.Service.Delete(difference.ID, difference.Account.Version)
+		default:
+			continue
+		}
+
+		if difference.Err == nil {
+			difference.Applied = true
+			applied++
+		}
+	}
+
+	return applied
+}
+
+// WriteDifferences writes differences as CSV with a row for every mismatched field.
+func WriteDifferences(w io.Writer, differences []Difference) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(differencesHeader); err != nil {
+		return err
+	}
+
+	for _, difference := range differences {
+		result := ""
+		switch {
+		case difference.Applied:
+			result = "applied"
+		case difference.Err != nil:
+			result = difference.Err.Error()
+		}
+
+		mismatches := difference.Mismatches
+		if len(mismatches) == 0 {
+			mismatches = []FieldMismatch{{}}
+		}
+
+		for _, mismatch := range mismatches {
+			record := []string{difference.ID, string(difference.Kind), mismatch.Field, mismatch.Ledger, mismatch.API, result}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func compare(entry form3.AccountRequest, account form3.Account, names []string) []FieldMismatch {
+	recorded := form3.Account{ID: entry.ID, OrganisationID: entry.OrganisationID, Attributes: entry.Attributes}
+
+	var mismatches []FieldMismatch
+	for _, name := range names {
+		ledgerValue, apiValue := Value(recorded, name), Value(account, name)
+		if ledgerValue != apiValue {
+			mismatches = append(mismatches, FieldMismatch{Field: name, Ledger: ledgerValue, API: apiValue})
+		}
+	}
+
+	return mismatches
+}
+
+// comparedColumns returns importable columns but id, in the order of Columns.
+func comparedColumns() []string {
+	var names []string
+	for _, name := range Columns {
+		if name != "id" && columns[name].set != nil {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
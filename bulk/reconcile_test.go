@@ -0,0 +1,64 @@
+package bulk
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ptrsd/form3"
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestReconciler(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	givenAccount(server, firstAccountID, testOrganisationID, `{"country":"GB","bank_id":"400300"}`)
+	givenAccount(server, secondAccountID, testOrganisationID, `{"country":"GB"}`)
+	givenAccount(server, otherOrganisationID, otherOrganisationID, `{"country":"GB"}`)
+
+	ledger, err := ReadLedger(strings.NewReader("id,country,bank_id\n"+
+		firstAccountID+",GB,400301\n"+
+		thirdAccountID+",FR,\n"), FormatCSV, nil, testOrganisationID)
+	thenEqual(t, "ReadLedgerError", nil, err)
+
+	reconciler := Reconciler{
+		Service:        form3.NewClient(nil, server.URL).AccountService,
+		OrganisationID: testOrganisationID,
+	}
+
+	t.Run("When reconciling then missing, extra and mismatched accounts are reported", func(t *testing.T) {
+		differences, err := reconciler.Reconcile(ledger)
+		thenEqual(t, "Error", nil, err)
+
+		out := bytes.Buffer{}
+		thenEqual(t, "WriteError", nil, WriteDifferences(&out, differences))
+		thenEqual(t, "Differences", "id,kind,field,ledger,api,result\n"+
+			thirdAccountID+",missing,,,,\n"+
+			secondAccountID+",extra,,,,\n"+
+			firstAccountID+",mismatch,bank_id,400301,400300,\n", out.String())
+	})
+
+	t.Run("When applying then missing accounts are created, extra ones deleted and mismatches only reported", func(t *testing.T) {
+		differences, err := reconciler.Reconcile(ledger)
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Applied", 2, reconciler.Apply(differences))
+
+		differences, err = reconciler.Reconcile(ledger)
+		thenEqual(t, "ReconcileError", nil, err)
+		thenEqual(t, "Remaining", 1, len(differences))
+		thenEqual(t, "RemainingKind", KindMismatch, differences[0].Kind)
+
+		mismatched, _ := server.Get(form3test.AccountsPath, firstAccountID)
+		thenEqual(t, "Version", 0, mismatched.Version)
+
+		_, ok := server.Get(form3test.AccountsPath, otherOrganisationID)
+		thenEqual(t, "OtherOrganisation", true, ok)
+	})
+
+	t.Run("When ledger row is invalid then error is returned", func(t *testing.T) {
+		_, err := ReadLedger(strings.NewReader("id,country\n"+firstAccountID+",GBR\n"), FormatCSV, nil, testOrganisationID)
+
+		thenEqual(t, "Error", `row 1: country: "GBR" is not an ISO 3166-1 code`, errorMessage(err))
+	})
+}
@@ -0,0 +1,134 @@
+package form3
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const defaultCacheEntries = 1000
+
+// FetchCache keeps fetched accounts in memory and serves AccountService.Fetch from it. Accounts older than TTL are
+// revalidated with conditional requests (If-None-Match and If-Modified-Since), so unchanged accounts are not
+// transferred again when the server supports them. Accounts are invalidated when they are created, updated or deleted
+// through the same Client. Set it as Client.AccountCache; it is safe for concurrent use.
+type FetchCache struct {
+	// TTL is how long a cached account is served without asking the server. Zero revalidates every Fetch.
+	TTL time.Duration
+	// MaxEntries limits the number of cached accounts, 1000 by default. The least recently used account is evicted
+	// first.
+	MaxEntries int
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+
+	mu            sync.Mutex
+	entries       map[string]*list.Element
+	order         *list.List
+	invalidations uint64
+}
+
+type cacheEntry struct {
+	id         string
+	account    Account
+	validators validators
+	storedAt   time.Time
+}
+
+// Invalidate removes the account from the cache, so the next Fetch gets it from the server.
+func (f *FetchCache) Invalidate(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.invalidations++
+	if element, ok := f.entries[id]; ok {
+		f.order.Remove(element)
+		delete(f.entries, id)
+	}
+}
+
+// Purge removes all accounts from the cache.
+func (f *FetchCache) Purge() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.invalidations++
+	f.entries = nil
+	f.order = nil
+}
+
+// Len returns the number of cached accounts.
+func (f *FetchCache) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.entries)
+}
+
+// get returns the cached entry, whether it is still fresh, and a token which put needs to store the result of the
+// fetch. A token taken before an invalidation keeps fetches which raced with a change from caching stale accounts.
+func (f *FetchCache) get(id string) (cacheEntry, bool, uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	element, ok := f.entries[id]
+	if !ok {
+		return cacheEntry{}, false, f.invalidations
+	}
+
+	f.order.MoveToFront(element)
+	entry := *element.Value.(*cacheEntry)
+	entry.account = cloneAccount(entry.account)
+
+	return entry, f.now().Sub(entry.storedAt) < f.TTL, f.invalidations
+}
+
+// put stores the account unless the cache has been invalidated since the token was taken.
+func (f *FetchCache) put(id string, account Account, validators validators, token uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if token != f.invalidations {
+		return
+	}
+
+	if f.entries == nil {
+		f.entries = map[string]*list.Element{}
+		f.order = list.New()
+	}
+
+	entry := &cacheEntry{id: id, account: cloneAccount(account), validators: validators, storedAt: f.now()}
+	if element, ok := f.entries[id]; ok {
+		element.Value = entry
+		f.order.MoveToFront(element)
+		return
+	}
+
+	f.entries[id] = f.order.PushFront(entry)
+
+	maxEntries := f.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheEntries
+	}
+	for f.order.Len() > maxEntries {
+		oldest := f.order.Back()
+		f.order.Remove(oldest)
+		delete(f.entries, oldest.Value.(*cacheEntry).id)
+	}
+}
+
+func (f *FetchCache) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+
+	return time.Now()
+}
+
+// cloneAccount copies slices of the account, so accounts handed to several callers do not share them.
+func cloneAccount(account Account) Account {
+	if names := account.Attributes.AlternativeBankAccountNames; names != nil {
+		account.Attributes.AlternativeBankAccountNames = append([]string(nil), names...)
+	}
+
+	return account
+}
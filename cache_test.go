@@ -0,0 +1,179 @@
+package form3
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestFetchCache(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	transport := &recordingTransport{}
+	client := NewClient(&http.Client{Transport: transport}, server.URL)
+	client.AccountCache = &FetchCache{TTL: time.Minute, Now: func() time.Time { return now }}
+
+	account, err := givenMinimalAccount(client)
+	if err != nil {
+		t.Fatalf("error while generating minimal account, %s", err.Error())
+	}
+
+	t.Run("When account is fresh then it is served from the cache", func(t *testing.T) {
+		transport.reset()
+
+		first, err := client.AccountService.Fetch(account.ID)
+		if err != nil {
+			t.Fatalf("fetch account returned with error %v", err.Error())
+		}
+		first.Attributes.Country = "FR"
+
+		now = now.Add(59 * time.Second)
+		second, err := client.AccountService.Fetch(account.ID)
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: second.Attributes.Country, expected: "GB", name: "Country"},
+			{actual: transport.statuses(), expected: []int{http.StatusOK}, name: "Statuses"},
+		})
+	})
+
+	t.Run("When account is stale then it is revalidated", func(t *testing.T) {
+		transport.reset()
+		now = now.Add(time.Minute)
+
+		fetched, err := client.AccountService.Fetch(account.ID)
+		_, _ = client.AccountService.Fetch(account.ID)
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: fetched.ID, expected: account.ID, name: "ID"},
+			{actual: transport.statuses(), expected: []int{http.StatusNotModified}, name: "Statuses"},
+			{actual: transport.conditional(), expected: 1, name: "Conditional"},
+		})
+	})
+
+	t.Run("When account changes on the server then stale account is fetched again", func(t *testing.T) {
+		transport.reset()
+		other := NewClient(nil, server.URL)
+		if _, err := other.AccountService.Update(AccountRequest{ID: account.ID, Attributes: AccountAttributes{BankID: "400300"}}); err != nil {
+			t.Fatalf("update account returned with error %v", err.Error())
+		}
+
+		cached, _ := client.AccountService.Fetch(account.ID)
+		now = now.Add(time.Minute)
+		fetched, _ := client.AccountService.Fetch(account.ID)
+
+		thenEquals(t, assertions{
+			{actual: cached.Version, expected: 0, name: "Cached.Version"},
+			{actual: fetched.Version, expected: 1, name: "Fetched.Version"},
+			{actual: fetched.Attributes.BankID, expected: "400300", name: "Fetched.BankID"},
+			{actual: transport.statuses(), expected: []int{http.StatusOK}, name: "Statuses"},
+		})
+	})
+
+	t.Run("When account is updated through the client then it is invalidated", func(t *testing.T) {
+		updated, err := client.AccountService.Update(AccountRequest{ID: account.ID, Version: 1, Attributes: AccountAttributes{BankID: "400301"}})
+		if err != nil {
+			t.Fatalf("update account returned with error %v", err.Error())
+		}
+		transport.reset()
+
+		fetched, _ := client.AccountService.Fetch(account.ID)
+
+		thenEquals(t, assertions{
+			{actual: fetched, expected: updated, name: "Account"},
+			{actual: transport.statuses(), expected: []int{http.StatusOK}, name: "Statuses"},
+			{actual: transport.conditional(), expected: 0, name: "Conditional"},
+		})
+	})
+
+	t.Run("When account is deleted through the client then it is not served", func(t *testing.T) {
+		if err := client.AccountService.Delete(account.ID, 2); err != nil {
+			t.Fatalf("delete account returned with error %v", err.Error())
+		}
+
+		_, err := client.AccountService.Fetch(account.ID)
+
+		thenEquals(t, assertions{
+			{actual: IsNotFound(err), expected: true, name: "IsNotFound"},
+			{actual: client.AccountCache.Len(), expected: 0, name: "Len"},
+		})
+	})
+
+	t.Run("When cache is full then least recently used account is evicted", func(t *testing.T) {
+		client.AccountCache = &FetchCache{TTL: time.Minute, MaxEntries: 2, Now: func() time.Time { return now }}
+
+		var ids []string
+		for idx := 0; idx < 3; idx++ {
+			account, err := givenMinimalAccount(client)
+			if err != nil {
+				t.Fatalf("error while generating minimal account, %s", err.Error())
+			}
+			ids = append(ids, account.ID)
+		}
+
+		_, _ = client.AccountService.Fetch(ids[0])
+		_, _ = client.AccountService.Fetch(ids[1])
+		_, _ = client.AccountService.Fetch(ids[0])
+		_, _ = client.AccountService.Fetch(ids[2])
+		transport.reset()
+
+		_, _ = client.AccountService.Fetch(ids[0])
+		_, _ = client.AccountService.Fetch(ids[2])
+		_, _ = client.AccountService.Fetch(ids[1])
+
+		thenEquals(t, assertions{
+			{actual: client.AccountCache.Len(), expected: 2, name: "Len"},
+			{actual: transport.statuses(), expected: []int{http.StatusOK}, name: "Statuses"},
+		})
+	})
+}
+
+// recordingTransport records statuses of responses and counts conditional requests.
+type recordingTransport struct {
+	mu           sync.Mutex
+	codes        []int
+	conditionals int
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req.Header.Get("If-None-Match") != "" {
+		r.conditionals++
+	}
+	if err == nil && req.Method == http.MethodGet {
+		r.codes = append(r.codes, resp.StatusCode)
+	}
+
+	return resp, err
+}
+
+func (r *recordingTransport) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.codes, r.conditionals = nil, 0
+}
+
+func (r *recordingTransport) statuses() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.codes
+}
+
+func (r *recordingTransport) conditional() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.conditionals
+}
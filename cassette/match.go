@@ -0,0 +1,63 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+)
+
+// Matcher tells whether a live request matches a recorded one.
+type Matcher func(live, recorded Request) bool
+
+// DefaultMatcher matches method, path, query parameters in any order and bodies. JSON bodies are compared as values,
+// so formatting and key order do not matter.
+func DefaultMatcher(live, recorded Request) bool {
+	return MatchMethodAndURL(live, recorded) && matchBody(live.Body, recorded.Body)
+}
+
+// MatchMethodAndURL matches method, path and query parameters in any order, ignoring bodies.
+func MatchMethodAndURL(live, recorded Request) bool {
+	if live.Method != recorded.Method {
+		return false
+	}
+
+	liveURL, err := url.Parse(live.URL)
+	if err != nil {
+		return false
+	}
+
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	return liveURL.Path == recordedURL.Path && reflect.DeepEqual(liveURL.Query(), recordedURL.Query())
+}
+
+// MatchMethodAndPath matches method and path, ignoring query parameters and bodies.
+func MatchMethodAndPath(live, recorded Request) bool {
+	liveURL, err := url.Parse(live.URL)
+	if err != nil {
+		return false
+	}
+
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	return live.Method == recorded.Method && liveURL.Path == recordedURL.Path
+}
+
+func matchBody(live, recorded string) bool {
+	if live == recorded {
+		return true
+	}
+
+	var liveValue, recordedValue interface{}
+	if json.Unmarshal([]byte(live), &liveValue) != nil || json.Unmarshal([]byte(recorded), &recordedValue) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(liveValue, recordedValue)
+}
@@ -0,0 +1,180 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Mode tells a Recorder whether to record or replay interactions.
+type Mode string
+
+const (
+	// ModeRecord forwards requests to the real transport and records interactions.
+	ModeRecord Mode = "record"
+	// ModeReplay serves recorded responses without any network access.
+	ModeReplay Mode = "replay"
+)
+
+// Recorder is an http.RoundTripper which records or replays interactions of a cassette.
+type Recorder struct {
+	// Transport sends requests in record mode, http.DefaultTransport if nil.
+	Transport http.RoundTripper
+	// Match tells whether a live request matches a recorded one, DefaultMatcher if nil. Both requests are scrubbed.
+	Match Matcher
+	// Scrubbers remove sensitive data from interactions before they are recorded or matched.
+	Scrubbers []Scrubber
+
+	mode     Mode
+	path     string
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New returns a Recorder for the cassette file. In replay mode the file is loaded and must exist. Scrubbers default to
+// DefaultScrubbers.
+func New(path string, mode Mode) (*Recorder, error) {
+	recorder := &Recorder{mode: mode, path: path, cassette: &Cassette{}, Scrubbers: DefaultScrubbers()}
+
+	switch mode {
+	case ModeRecord:
+	case ModeReplay:
+		cassette, err := Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: error while loading %s, %w", path, err)
+		}
+		recorder.cassette = cassette
+		recorder.used = make([]bool, len(cassette.Interactions))
+	default:
+		return nil, fmt.Errorf("cassette: unknown mode %q, expected record or replay", mode)
+	}
+
+	return recorder, nil
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// RoundTrip records or replays a single interaction.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := r.newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	return r.record(req, recorded)
+}
+
+// Save writes recorded interactions to the cassette file. It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.Save(r.path)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Request:  recorded,
+		Response: Response{StatusCode: resp.StatusCode, Header: resp.Header.Clone(), Body: string(body)},
+	}
+	r.scrub(&interaction)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the request, so repeated requests are served in recorded order.
+func (r *Recorder) replay(req *http.Request, live Request) (*http.Response, error) {
+	match := r.Match
+	if match == nil {
+		match = DefaultMatcher
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for idx, interaction := range r.cassette.Interactions {
+		if r.used[idx] || !match(live, interaction.Request) {
+			continue
+		}
+		r.used[idx] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette: no recorded interaction for %s %s", live.Method, live.URL)
+}
+
+// newRequest reads the request body, restoring it for the transport, and returns the scrubbed request.
+func (r *Recorder) newRequest(req *http.Request) (Request, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return Request{}, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	interaction := Interaction{Request: Request{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+		Header: req.Header.Clone(),
+		Body:   string(body),
+	}}
+	r.scrub(&interaction)
+
+	return interaction.Request, nil
+}
+
+func (r *Recorder) scrub(interaction *Interaction) {
+	for _, scrubber := range r.Scrubbers {
+		scrubber(interaction)
+	}
+}
@@ -0,0 +1,113 @@
+package cassette
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatalf("error while creating directory, %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cassettes", "accounts.json")
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"call":` + strconv.Itoa(calls) + `,"request":` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	request := `{"data":{"id":"1","attributes":{"bank_account_name":"Jane Doe","country":"GB"}}}`
+
+	t.Run("When recording then scrubbed interactions are saved", func(t *testing.T) {
+		recorder, err := New(path, ModeRecord)
+		thenEqual(t, "Error", nil, err)
+
+		client := &http.Client{Transport: recorder}
+		thenEqual(t, "First", `{"call":1,"request":`+request+`}`, whenPosting(t, client, server.URL+"/v1/accounts?a=1&b=2", request))
+		thenEqual(t, "Second", `{"call":2,"request":`+request+`}`, whenPosting(t, client, server.URL+"/v1/accounts?a=1&b=2", request))
+		thenEqual(t, "SaveError", nil, recorder.Save())
+
+		cassette, err := Load(path)
+		thenEqual(t, "LoadError", nil, err)
+		thenEqual(t, "Interactions", 2, len(cassette.Interactions))
+		thenEqual(t, "URL", "/v1/accounts?a=1&b=2", cassette.Interactions[0].Request.URL)
+		thenEqual(t, "Authorization", Redacted, cassette.Interactions[0].Request.Header.Get("Authorization"))
+		thenEqual(t, "RequestBody", `{"data":{"attributes":{"bank_account_name":"REDACTED","country":"GB"},"id":"1"}}`,
+			cassette.Interactions[0].Request.Body)
+		thenEqual(t, "ContainsPII", false, strings.Contains(cassette.Interactions[1].Response.Body, "Jane"))
+	})
+
+	t.Run("When replaying then recorded responses are served in order without network", func(t *testing.T) {
+		server.Close()
+
+		recorder, err := New(path, ModeReplay)
+		thenEqual(t, "Error", nil, err)
+
+		client := &http.Client{Transport: recorder}
+		first := whenPosting(t, client, "http://replay.invalid/v1/accounts?b=2&a=1", request)
+		second := whenPosting(t, client, "http://replay.invalid/v1/accounts?b=2&a=1", request)
+
+		thenEqual(t, "First", true, strings.HasPrefix(first, `{"call":1,`))
+		thenEqual(t, "Second", true, strings.HasPrefix(second, `{"call":2,`))
+
+		_, err = client.Post("http://replay.invalid/v1/accounts?b=2&a=1", "application/json", strings.NewReader(request))
+		thenEqual(t, "Exhausted", true, err != nil && strings.Contains(err.Error(), "no recorded interaction for POST /v1/accounts?b=2&a=1"))
+	})
+
+	t.Run("When cassette does not exist in replay mode then error is returned", func(t *testing.T) {
+		_, err := New(filepath.Join(dir, "missing.json"), ModeReplay)
+		thenEqual(t, "Error", true, err != nil)
+	})
+}
+
+func TestDefaultMatcher(t *testing.T) {
+	recorded := Request{Method: http.MethodGet, URL: "/v1/accounts?page[number]=0&page[size]=5", Body: `{"a":1,"b":2}`}
+
+	thenEqual(t, "QueryOrder", true, DefaultMatcher(Request{Method: http.MethodGet, URL: "/v1/accounts?page[size]=5&page[number]=0", Body: `{"b":2, "a":1}`}, recorded))
+	thenEqual(t, "Query", false, DefaultMatcher(Request{Method: http.MethodGet, URL: "/v1/accounts?page[size]=6&page[number]=0", Body: `{"a":1,"b":2}`}, recorded))
+	thenEqual(t, "Body", false, DefaultMatcher(Request{Method: http.MethodGet, URL: recorded.URL, Body: `{"a":2}`}, recorded))
+	thenEqual(t, "Path", true, MatchMethodAndPath(Request{Method: http.MethodGet, URL: "/v1/accounts"}, recorded))
+}
+
+func whenPosting(t *testing.T, client *http.Client, url, body string) string {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("error while creating request, %s", err.Error())
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("error while sending request, %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("error while reading response, %s", err.Error())
+	}
+
+	return string(raw)
+}
+
+func thenEqual(t *testing.T, name string, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s:\nExpected: %#v\n  Actual: %#v", name, expected, actual)
+	}
+}
@@ -0,0 +1,110 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Redacted replaces scrubbed values.
+const Redacted = "REDACTED"
+
+// Scrubber removes sensitive data from an interaction. Scrubbers are applied to live requests too, so scrubbed
+// requests still match.
+type Scrubber func(*Interaction)
+
+// PIIFields are JSON fields holding personal data of account holders.
+var PIIFields = []string{
+	"account_number",
+	"alternative_bank_account_names",
+	"bank_account_name",
+	"first_name",
+	"iban",
+	"secondary_identification",
+	"title",
+}
+
+// DefaultScrubbers redact credentials and PIIFields.
+func DefaultScrubbers() []Scrubber {
+	return []Scrubber{
+		ScrubHeaders("Authorization", "Cookie", "Set-Cookie"),
+		ScrubJSONFields(PIIFields...),
+	}
+}
+
+// ScrubHeaders redacts request and response headers.
+func ScrubHeaders(names ...string) Scrubber {
+	return func(interaction *Interaction) {
+		for _, name := range names {
+			redactHeader(interaction.Request.Header, name)
+			redactHeader(interaction.Response.Header, name)
+		}
+	}
+}
+
+// ScrubJSONFields redacts fields with the given names at any depth of JSON request and response bodies. String values
+// are replaced with Redacted and arrays with a single Redacted element. Bodies which are not JSON are left as they are.
+func ScrubJSONFields(names ...string) Scrubber {
+	scrubbed := make(map[string]bool, len(names))
+	for _, name := range names {
+		scrubbed[name] = true
+	}
+
+	return func(interaction *Interaction) {
+		interaction.Request.Body = scrubJSON(interaction.Request.Body, scrubbed)
+		interaction.Response.Body = scrubJSON(interaction.Response.Body, scrubbed)
+	}
+}
+
+func redactHeader(header http.Header, name string) {
+	if header.Get(name) != "" {
+		header.Set(name, Redacted)
+	}
+}
+
+func scrubJSON(body string, names map[string]bool) string {
+	var value interface{}
+	if body == "" || json.Unmarshal([]byte(body), &value) != nil {
+		return body
+	}
+
+	if !scrubValue(value, names) {
+		return body
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+
+	return string(raw)
+}
+
+// scrubValue redacts fields in place and returns true if anything has been redacted.
+func scrubValue(value interface{}, names map[string]bool) bool {
+	changed := false
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if names[key] {
+				switch item.(type) {
+				case []interface{}:
+					v[key] = []interface{}{Redacted}
+				case nil:
+					continue
+				default:
+					v[key] = Redacted
+				}
+				changed = true
+				continue
+			}
+			changed = scrubValue(item, names) || changed
+		}
+	case []interface{}:
+		for _, item := range v {
+			changed = scrubValue(item, names) || changed
+		}
+	}
+
+	return changed
+}
@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ptrsd/form3/jsonapi"
 )
 
 const (
 	version          = "v1"
 	defaultUserAgent = "form3-client/" + version
 	defaultBaseURL   = "http://localhost:8080"
-	contentType      = "application/vnd.api+json"
+	contentType      = jsonapi.MediaType
 
 	defaultPageSize = "100"
 )
@@ -23,13 +29,50 @@ type Client struct {
 	httpClient *http.Client
 
 	// BaseURL is a base url for Form3 server. Default value: http://localhost:8080
-	BaseURL        *url.URL
-	UserAgent      string
-	AccountService *AccountService
+	BaseURL   *url.URL
+	UserAgent string
+	// OrganisationID is used by creates of requests without an organisation ID, and filters lists of accounts and
+	// payments. Requests are not scoped to an organisation if it is empty.
+	OrganisationID string
+	// Token is a bearer token sent with every request. Requests are not authenticated if it is empty.
+	Token string
+	// Credentials provides bearer tokens instead of Token. Requests rejected with 401 or 403 are sent once more with
+	// credentials read again, so rotated tokens are picked up.
+	Credentials CredentialProvider
+	// Signer signs every request, replacing the bearer token. Requests are not signed if it is nil.
+	Signer Signer
+	// CircuitBreaker fails requests fast while the server keeps failing. Requests are always sent if it is nil.
+	CircuitBreaker *CircuitBreaker
+	// Mode guards against changing resources by accident, ModeNormal by default.
+	Mode Mode
+	// Logger logs requests which are not sent in ModeDryRun, the standard logger if nil.
+	Logger *log.Logger
+	// AccountCache serves AccountService.Fetch from memory. Accounts are always fetched from the server if it is nil.
+	AccountCache *FetchCache
+
+	AccountService      *AccountService
+	OrganisationService *OrganisationService
+	PaymentService      *PaymentService
+	SubscriptionService *SubscriptionService
+	MandateService      *MandateService
+	ReportService       *ReportService
+	UserService         *UserService
+	RoleService         *RoleService
+	ACEService          *ACEService
+	CredentialService   *CredentialService
+	AuditService        *AuditService
+
+	AccountIdentificationService *AccountIdentificationService
+	PaymentReturnService         *PaymentReturnService
+	PaymentReversalService       *PaymentReversalService
+	PaymentRecallService         *PaymentRecallService
 }
 
+// ErrorMessage is a body of a non-2xx response. Form3 reports most errors with error_message, JSON:API errors are
+// used when it is empty.
 type ErrorMessage struct {
-	ErrorMessage string `json:"error_message"`
+	ErrorMessage string          `json:"error_message"`
+	Errors       []jsonapi.Error `json:"errors,omitempty"`
 }
 
 //ListOptions defines page number and size of a page for a list operation.
@@ -53,11 +96,40 @@ func NewClient(httpClient *http.Client, baseURL string) (client *Client) {
 	userBaseURL, _ := url.Parse(baseURL)
 	client = &Client{httpClient: httpClient, BaseURL: userBaseURL, UserAgent: defaultUserAgent}
 
-	client.AccountService = &AccountService{client}
+	client.initServices()
 
 	return client
 }
 
+// ForOrganisation returns a view of the client scoped to the organisation, so a single client can serve many
+// organisations. The view copies the configuration of the client, and shares its HTTP client, circuit breaker, cache
+// and credentials.
+func (c *Client) ForOrganisation(id string) *Client {
+	scoped := *c
+	scoped.OrganisationID = id
+	scoped.initServices()
+
+	return &scoped
+}
+
+func (c *Client) initServices() {
+	c.AccountService = &AccountService{client: c}
+	c.OrganisationService = &OrganisationService{c}
+	c.PaymentService = &PaymentService{c}
+	c.SubscriptionService = &SubscriptionService{c}
+	c.AccountIdentificationService = &AccountIdentificationService{c}
+	c.MandateService = &MandateService{c}
+	c.PaymentReturnService = &PaymentReturnService{c}
+	c.PaymentReversalService = &PaymentReversalService{c}
+	c.PaymentRecallService = &PaymentRecallService{c}
+	c.ReportService = &ReportService{c}
+	c.UserService = &UserService{c}
+	c.RoleService = &RoleService{c}
+	c.ACEService = &ACEService{c}
+	c.CredentialService = &CredentialService{c}
+	c.AuditService = &AuditService{c}
+}
+
 func (c *Client) newRequest(method string, url *url.URL, body interface{}) (req *http.Request, err error) {
 	reqURL := c.BaseURL.ResolveReference(url)
 	buf := bytes.Buffer{}
@@ -81,11 +153,114 @@ func (c *Client) newRequest(method string, url *url.URL, body interface{}) (req
 	req.Header.Set("Accept", contentType)
 	req.Header.Set("User-Agent", defaultUserAgent)
 
+	if err = c.authorize(req); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
+// createResource posts data wrapped in a JSON:API document and decodes the created resource into result.
+func (c *Client) createResource(path string, data, result interface{}) error {
+	req, err := c.newRequest(http.MethodPost, &url.URL{Path: path}, jsonapi.Document{Data: data})
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, &jsonapi.Document{Data: result})
+}
+
+// fetchResource gets a single resource and decodes it into result.
+func (c *Client) fetchResource(path string, result interface{}) error {
+	req, err := c.newRequest(http.MethodGet, &url.URL{Path: path}, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, &jsonapi.Document{Data: result})
+}
+
+// validators identify a fetched version of a resource in conditional requests.
+type validators struct {
+	etag         string
+	lastModified string
+}
+
+// fetchConditionally gets a single resource unless it has not been modified since it was fetched with the cached
+// validators. It decodes the resource into result and returns its validators, or returns true if the resource has not
+// been modified.
+func (c *Client) fetchConditionally(path string, cached validators, result interface{}) (validators, bool, error) {
+	req, err := c.newRequest(http.MethodGet, &url.URL{Path: path}, nil)
+	if err != nil {
+		return validators{}, false, err
+	}
+
+	if cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	if cached.lastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.lastModified)
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return validators{}, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return cached, true, nil
+	}
+
+	if err := checkError(resp); err != nil {
+		return validators{}, false, err
+	}
+
+	fetched := validators{etag: resp.Header.Get("ETag"), lastModified: resp.Header.Get("Last-Modified")}
+	err = json.NewDecoder(resp.Body).Decode(&jsonapi.Document{Data: result})
+
+	return fetched, false, err
+}
+
+// listResources gets a page of a collection, decodes it into result, and returns true if there are more pages.
+func (c *Client) listResources(path string, query url.Values, result interface{}) (bool, error) {
+	req, err := c.newRequest(http.MethodGet, &url.URL{Path: path, RawQuery: query.Encode()}, nil)
+	if err != nil {
+		return false, err
+	}
+
+	doc := jsonapi.Document{Data: result}
+	err = c.do(req, &doc)
+
+	return doc.Links.HasNext(), err
+}
+
+// updateResource patches a resource with data wrapped in a JSON:API document and decodes the result into result.
+func (c *Client) updateResource(path string, data, result interface{}) error {
+	req, err := c.newRequest(http.MethodPatch, &url.URL{Path: path}, jsonapi.Document{Data: data})
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, &jsonapi.Document{Data: result})
+}
+
+// deleteResource deletes a resource in the given version.
+func (c *Client) deleteResource(path string, version int) error {
+	deleteQuery := url.Values{
+		"version": {strconv.Itoa(version)},
+	}
+
+	req, err := c.newRequest(http.MethodDelete, &url.URL{Path: path, RawQuery: deleteQuery.Encode()}, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, nil)
+}
+
 func (c *Client) do(req *http.Request, respType interface{}) error {
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -102,6 +277,89 @@ func (c *Client) do(req *http.Request, respType interface{}) error {
 	return err
 }
 
+// send signs and sends the request unless the client mode stops it, and sends it once more if the server rejects
+// credentials of the provider.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if resp, stopped, err := c.guard(req); stopped {
+		return resp, err
+	}
+
+	resp, err := c.transmit(req)
+	if err != nil || !c.rejected(resp) {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	retry, err := c.reauthorize(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.transmit(retry)
+}
+
+// transmit signs the request and sends it through the circuit breaker, if there is one. Signing is the last step, so
+// headers set after newRequest are covered by the signature.
+func (c *Client) transmit(req *http.Request) (*http.Response, error) {
+	if c.Signer != nil {
+		if err := c.Signer.Sign(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.CircuitBreaker == nil {
+		return c.httpClient.Do(req)
+	}
+
+	operation := Operation(req.Method, req.URL.Path)
+	if err := c.CircuitBreaker.allow(operation); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	c.CircuitBreaker.record(operation, isFailure(resp, err))
+
+	return resp, err
+}
+
+// resourcePath joins a collection path with IDs of nested resources.
+func resourcePath(basePath string, ids ...string) string {
+	path := basePath
+	for _, id := range ids {
+		path = fmt.Sprintf("%s/%s", path, id)
+	}
+
+	return path
+}
+
+// pagingQuery converts ListOptions into query parameters, the default page size is used when none is set.
+func pagingQuery(options ListOptions) url.Values {
+	pageSize := defaultPageSize
+	if options.PageSize != 0 {
+		pageSize = strconv.Itoa(options.PageSize)
+	}
+
+	return url.Values{
+		"page[number]": {strconv.Itoa(options.Page)},
+		"page[size]":   {pageSize},
+	}
+}
+
+// stream sends the request and returns the response body unread. The caller is responsible for closing it.
+func (c *Client) stream(req *http.Request) (io.ReadCloser, error) {
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkError(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
 func checkError(resp *http.Response) error {
 	switch resp.StatusCode {
 	case 200, 201, 204:
@@ -109,19 +367,29 @@ func checkError(resp *http.Response) error {
 	case 400, 401, 403, 404, 405, 406, 409, 429, 500, 502, 503, 504:
 		errMsg := &ErrorMessage{}
 
+		// Proxies and load balancers answer with HTML or empty bodies, the status is all there is to report then.
 		if err := json.NewDecoder(resp.Body).Decode(errMsg); err != nil {
-			return err
+			return &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
+		}
+
+		if errMsg.ErrorMessage == "" && len(errMsg.Errors) > 0 {
+			details := make([]string, 0, len(errMsg.Errors))
+			for _, apiErr := range errMsg.Errors {
+				details = append(details, apiErr.Error())
+			}
+
+			errMsg.ErrorMessage = strings.Join(details, "\n")
 		}
 
 		if errMsg.ErrorMessage == "" {
-			return fmt.Errorf(resp.Status)
+			return &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
 		}
 
 		newLineRegex := regexp.MustCompile(`\n`)
 		errMsg.ErrorMessage = newLineRegex.ReplaceAllString(errMsg.ErrorMessage, ", ")
 
-		return fmt.Errorf(errMsg.ErrorMessage)
+		return &APIError{StatusCode: resp.StatusCode, Message: errMsg.ErrorMessage}
 	default:
-		return fmt.Errorf("unknown status code %d", resp.StatusCode)
+		return &APIError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("unknown status code %d", resp.StatusCode)}
 	}
 }
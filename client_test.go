@@ -1,12 +1,18 @@
 package form3
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"syscall"
 	"testing"
+
+	"github.com/ptrsd/form3/fault"
+	"github.com/ptrsd/form3/form3test"
 )
 
 type assertion struct {
@@ -115,6 +121,186 @@ func Test_whenCallingNotExistingServiceThenReturnError(t *testing.T) {
 	})
 }
 
+func Test_whenErrorResponseHasJSONAPIErrorsThenReturnTheirDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"errors":[{"detail":"name is required"},{"title":"Invalid country"}]}`)
+	}))
+	defer server.Close()
+
+	client := testClient(server.URL)
+
+	req, err := client.newRequest(http.MethodGet, &url.URL{Path: "/"}, nil)
+	if err != nil {
+		t.Errorf("error while creating new request, %s", err.Error())
+	}
+
+	err = client.do(req, nil)
+	assertNotNil(t, assertions{
+		{actual: err, name: "Client.JSONAPIError"},
+	})
+
+	thenEquals(t, assertions{
+		{actual: err.Error(), expected: "name is required, Invalid country", name: "Client.JSONAPIErrorMessage"},
+	})
+}
+
+func Test_whenNotFoundThenReturnAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error_message":"record does not exist"}`)
+	}))
+	defer server.Close()
+
+	client := testClient(server.URL)
+
+	req, err := client.newRequest(http.MethodGet, &url.URL{Path: "/"}, nil)
+	if err != nil {
+		t.Errorf("error while creating new request, %s", err.Error())
+	}
+
+	err = client.do(req, nil)
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Client.APIError: expected *APIError, got %#v", err)
+	}
+
+	thenEquals(t, assertions{
+		{actual: apiErr.StatusCode, expected: http.StatusNotFound, name: "Client.APIError.StatusCode"},
+		{actual: IsNotFound(err), expected: true, name: "Client.IsNotFound"},
+		{actual: IsConflict(err), expected: false, name: "Client.IsConflict"},
+	})
+}
+
+func Test_whenErrorResponseIsNotJSONThenReturnAPIErrorWithStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprintln(w, `<html><body>Bad Gateway</body></html>`)
+	}))
+	defer server.Close()
+
+	client := testClient(server.URL)
+
+	req, err := client.newRequest(http.MethodGet, &url.URL{Path: "/"}, nil)
+	if err != nil {
+		t.Errorf("error while creating new request, %s", err.Error())
+	}
+
+	err = client.do(req, nil)
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Client.APIError: expected *APIError, got %#v", err)
+	}
+
+	thenEquals(t, assertions{
+		{actual: apiErr.StatusCode, expected: http.StatusBadGateway, name: "Client.APIError.StatusCode"},
+		{actual: err.Error(), expected: "502 Bad Gateway", name: "Client.APIError.Message"},
+	})
+}
+
+func Test_whenTransportIsFaultyThenServicesReturnErrors(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	transport := &fault.Transport{Script: []fault.Fault{
+		fault.NonJSONError(http.StatusServiceUnavailable),
+		fault.Reset(),
+		fault.TruncatedBody(),
+		fault.Status(http.StatusTooManyRequests, `{"error_message":"rate limit exceeded"}`),
+	}}
+	client := NewClient(&http.Client{Transport: transport}, server.URL)
+
+	_, _, err := client.AccountService.List(ListOptions{})
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("NonJSON.APIError: expected *APIError, got %#v", err)
+	}
+	thenEquals(t, assertions{
+		{actual: apiErr.StatusCode, expected: http.StatusServiceUnavailable, name: "NonJSON.StatusCode"},
+	})
+
+	_, _, err = client.AccountService.List(ListOptions{})
+	thenEquals(t, assertions{
+		{actual: errors.Is(err, syscall.ECONNRESET), expected: true, name: "Reset"},
+	})
+
+	_, _, err = client.AccountService.List(ListOptions{})
+	thenEquals(t, assertions{
+		{actual: errors.Is(err, io.ErrUnexpectedEOF), expected: true, name: "Truncated"},
+	})
+
+	_, _, err = client.AccountService.List(ListOptions{})
+	thenEquals(t, assertions{
+		{actual: err.Error(), expected: "rate limit exceeded", name: "Status.Message"},
+	})
+}
+
+func Test_whenClientIsScopedToOrganisationThenCreatesAndListsUseIt(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	const (
+		firstOrganisationID  = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+		secondOrganisationID = "bfb86474-e82a-497f-8b65-c8a2c7f2fa44"
+	)
+
+	client := NewClient(nil, server.URL)
+	client.Token = "secret"
+	first := client.ForOrganisation(firstOrganisationID)
+	second := client.ForOrganisation(secondOrganisationID)
+
+	for idx, scoped := range []*Client{first, first, second} {
+		_, err := scoped.AccountService.Create(AccountRequest{
+			ID:         fmt.Sprintf("00000000-0000-4000-8000-00000000000%d", idx),
+			Attributes: AccountAttributes{Country: "GB"},
+		})
+		if err != nil {
+			t.Fatalf("create account returned with error %v", err.Error())
+		}
+	}
+
+	explicit, err := first.AccountService.Create(AccountRequest{
+		ID:             "00000000-0000-4000-8000-000000000009",
+		OrganisationID: secondOrganisationID,
+		Attributes:     AccountAttributes{Country: "GB"},
+	})
+	if err != nil {
+		t.Fatalf("create account returned with error %v", err.Error())
+	}
+
+	firstAccounts, _, firstErr := first.AccountService.List(ListOptions{})
+	secondAccounts, _, secondErr := second.AccountService.List(ListOptions{})
+	allAccounts, _, allErr := client.AccountService.List(ListOptions{})
+
+	thenEquals(t, assertions{
+		{actual: firstErr, expected: nil, name: "First.Error"},
+		{actual: secondErr, expected: nil, name: "Second.Error"},
+		{actual: allErr, expected: nil, name: "All.Error"},
+		{actual: len(firstAccounts), expected: 2, name: "First.Accounts"},
+		{actual: firstAccounts[0].OrganisationID, expected: firstOrganisationID, name: "First.OrganisationID"},
+		{actual: len(secondAccounts), expected: 2, name: "Second.Accounts"},
+		{actual: explicit.OrganisationID, expected: secondOrganisationID, name: "Explicit.OrganisationID"},
+		{actual: len(allAccounts), expected: 4, name: "All.Accounts"},
+		{actual: first.Token, expected: "secret", name: "First.Token"},
+		{actual: first.AccountService.client, expected: first, name: "First.AccountService"},
+		{actual: client.OrganisationID, expected: "", name: "Client.OrganisationID"},
+	})
+}
+
+func Test_whenTokenIsSetThenAuthorizationHeaderIsSent(t *testing.T) {
+	client := testClient("")
+	client.Token = "secret"
+
+	request, err := client.newRequest(http.MethodGet, &url.URL{Path: "/"}, nil)
+	if err != nil {
+		t.Errorf("error while creating new request, %s", err.Error())
+	}
+
+	thenEquals(t, assertions{
+		{actual: request.Header.Get("Authorization"), expected: "Bearer secret", name: "Client.Authorization"},
+	})
+}
+
 func testClient(addr string) *Client {
 	testURL, _ := url.Parse(addr)
 	client := &Client{
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ptrsd/form3"
+)
+
+func (c *command) accounts(args []string) error {
+	subcommands := map[string]func([]string) error{
+		"create":    c.createAccount,
+		"fetch":     c.fetchAccount,
+		"list":      c.listAccounts,
+		"delete":    c.deleteAccount,
+		"import":    c.importAccounts,
+		"export":    c.exportAccounts,
+		"reconcile": c.reconcileAccounts,
+		"plan":      c.planAccounts,
+		"apply":     c.applyAccounts,
+	}
+
+	if len(args) == 0 || subcommands[args[0]] == nil {
+		fmt.Fprintln(c.stderr, "Usage: form3 accounts <create|fetch|list|delete|import|export|reconcile|plan|apply> [flags]")
+		return errUsage
+	}
+
+	return subcommands[args[0]](args[1:])
+}
+
+func (c *command) createAccount(args []string) error {
+	flags := c.flagSet("accounts create", "[flags]")
+
+	file := flags.String("file", "", "read the account request as JSON from a file, - for stdin")
+	id := flags.String("id", "", "account ID, a random UUID by default")
+	organisationID := flags.String("organisation-id", "", "organisation ID, the organisation of the profile by default")
+	attributes := form3.AccountAttributes{}
+	flags.StringVar(&attributes.Country, "country", "", "ISO 3166-1 country code")
+	flags.StringVar(&attributes.BaseCurrency, "base-currency", "", "ISO 4217 currency code")
+	flags.StringVar(&attributes.BankID, "bank-id", "", "bank ID, e.g. a sort code")
+	flags.StringVar(&attributes.BankIDCode, "bank-id-code", "", "type of the bank ID, e.g. GBDSC")
+	flags.StringVar(&attributes.Bic, "bic", "", "SWIFT BIC")
+	flags.StringVar(&attributes.AccountNumber, "account-number", "", "account number")
+	flags.StringVar(&attributes.Iban, "iban", "", "IBAN")
+	flags.StringVar(&attributes.BankAccountName, "name", "", "name of the account holder")
+
+	if err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	createReq := form3.AccountRequest{ID: *id, OrganisationID: *organisationID, Attributes: attributes}
+	if *file != "" {
+		if err := c.readJSON(*file, &createReq); err != nil {
+			return err
+		}
+	}
+
+	if createReq.ID == "" {
+		generated, err := newUUID()
+		if err != nil {
+			return err
+		}
+		createReq.ID = generated
+	}
+
+	account, err := c.client.AccountService.Create(createReq)
+	if err != nil {
+		return err
+	}
+
+	return c.printAccounts(account)
+}
+
+func (c *command) fetchAccount(args []string) error {
+	flags := c.flagSet("accounts fetch", "<id>")
+	if err := c.parse(flags, args, 1); err != nil {
+		return err
+	}
+
+	account, err := c.client.AccountService.Fetch(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return c.printAccounts(account)
+}
+
+func (c *command) listAccounts(args []string) error {
+	flags := c.flagSet("accounts list", "[flags]")
+	page := flags.Int("page", 0, "page number")
+	pageSize := flags.Int("page-size", 0, "page size, the server default if not set")
+	all := flags.Bool("all", false, "list all pages")
+
+	if err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	var accounts []form3.Account
+	for hasNext := true; hasNext; *page++ {
+		var (
+			list []form3.Account
+			err  error
+		)
+
+		list, hasNext, err = c.client.AccountService.List(form3.ListOptions{Page: *page, PageSize: *pageSize})
+		if err != nil {
+			return err
+		}
+
+		accounts = append(accounts, list...)
+		hasNext = hasNext && *all
+	}
+
+	return c.printAccountList(accounts)
+}
+
+func (c *command) deleteAccount(args []string) error {
+	flags := c.flagSet("accounts delete", "[flags] <id>")
+	version := flags.Int("version", -1, "version of the account, the current version by default")
+
+	if err := c.parse(flags, args, 1); err != nil {
+		return err
+	}
+
+	id := flags.Arg(0)
+	if *version < 0 {
+		return c.client.AccountService.DeleteLatest(id)
+	}
+
+	return c.client.AccountService.Delete(id, *version)
+}
+
+func (c *command) flagSet(name, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: form3 %s %s\n", name, arguments)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+// parse parses flags and checks that exactly nArgs positional arguments are left.
+func (c *command) parse(flags *flag.FlagSet, args []string, nArgs int) error {
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	if flags.NArg() != nArgs {
+		flags.Usage()
+		return errUsage
+	}
+
+	return nil
+}
+
+func (c *command) readJSON(path string, v interface{}) error {
+	if path == "-" {
+		return json.NewDecoder(c.stdin).Decode(v)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewDecoder(file).Decode(v)
+}
+
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ptrsd/form3/bulk"
+)
+
+func (c *command) importAccounts(args []string) error {
+	flags := c.flagSet("accounts import", "[flags]")
+	file := flags.String("file", "-", "CSV or NDJSON file to import, - for stdin")
+	format := flags.String("format", "", "file format: csv or ndjson, guessed from the file extension by default")
+	reportPath := flags.String("report", "-", "file to write the per-row CSV report to, - for stdout")
+	resume := flags.String("resume", "", "report of a previous run, rows created by that run are skipped")
+	workers := flags.Int("workers", 4, "number of concurrent requests")
+	organisationID := flags.String("organisation-id", c.client.OrganisationID, "organisation ID of rows which do not have one")
+	mapping := flags.String("map", "", "comma separated header=column pairs mapping CSV headers to account columns")
+
+	if err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	importer := bulk.Importer{
+		Service:        c.client.AccountService,
+		Workers:        *workers,
+		OrganisationID: *organisationID,
+	}
+
+	var err error
+	if importer.Mapping, err = parseMapping(*mapping); err != nil {
+		return err
+	}
+
+	if *resume != "" {
+		if importer.Done, err = readReport(*resume); err != nil {
+			return err
+		}
+	}
+
+	in, err := c.open(*file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	report, err := c.create(*reportPath)
+	if err != nil {
+		return err
+	}
+	defer report.Close()
+
+	summary, err := importer.Import(in, fileFormat(*format, *file), report)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "%d rows: %d created, %d existing, %d skipped, %d invalid, %d failed\n",
+		summary.Total, summary.Created, summary.Existing, summary.Skipped, summary.Invalid, summary.Failed)
+
+	if summary.Invalid+summary.Failed > 0 {
+		return fmt.Errorf("%d rows have not been imported, see the report", summary.Invalid+summary.Failed)
+	}
+
+	return nil
+}
+
+func (c *command) exportAccounts(args []string) error {
+	flags := c.flagSet("accounts export", "[flags]")
+	file := flags.String("file", "-", "file to write accounts to, - for stdout")
+	format := flags.String("format", "", "file format: csv or ndjson, guessed from the file extension by default")
+	columns := flags.String("columns", "", "comma separated CSV columns, all columns by default")
+	pageSize := flags.Int("page-size", 0, "page size, the server default if not set")
+	organisationID := flags.String("organisation-id", c.client.OrganisationID, "export only accounts of this organisation")
+
+	if err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	exporter := bulk.Exporter{
+		Service:        c.client.AccountService,
+		PageSize:       *pageSize,
+		OrganisationID: *organisationID,
+	}
+	if *columns != "" {
+		exporter.Columns = strings.Split(*columns, ",")
+	}
+
+	out, err := c.create(*file)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	count, err := exporter.Export(out, fileFormat(*format, *file))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "%d accounts exported\n", count)
+	return nil
+}
+
+func (c *command) reconcileAccounts(args []string) error {
+	flags := c.flagSet("accounts reconcile", "[flags]")
+	file := flags.String("file", "-", "CSV or NDJSON ledger, - for stdin")
+	format := flags.String("format", "", "file format: csv or ndjson, guessed from the file extension by default")
+	mapping := flags.String("map", "", "comma separated header=column pairs mapping CSV headers to account columns")
+	columns := flags.String("columns", "", "comma separated columns to compare, all importable columns by default")
+	pageSize := flags.Int("page-size", 0, "page size, the server default if not set")
+	organisationID := flags.String("organisation-id", c.client.OrganisationID, "reconcile only accounts of this organisation")
+	apply := flags.Bool("apply", false, "create missing accounts and delete extra ones")
+
+	if err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	headers, err := parseMapping(*mapping)
+	if err != nil {
+		return err
+	}
+
+	in, err := c.open(*file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	ledger, err := bulk.ReadLedger(in, fileFormat(*format, *file), headers, *organisationID)
+	if err != nil {
+		return err
+	}
+
+	reconciler := bulk.Reconciler{
+		Service:        c.client.AccountService,
+		PageSize:       *pageSize,
+		OrganisationID: *organisationID,
+	}
+	if *columns != "" {
+		reconciler.Columns = strings.Split(*columns, ",")
+	}
+
+	differences, err := reconciler.Reconcile(ledger)
+	if err != nil {
+		return err
+	}
+
+	applied := 0
+	if *apply {
+		applied = reconciler.Apply(differences)
+	}
+
+	if err := bulk.WriteDifferences(c.stdout, differences); err != nil {
+		return err
+	}
+
+	if remaining := len(differences) - applied; remaining > 0 {
+		return fmt.Errorf("%d differences remain", remaining)
+	}
+
+	return nil
+}
+
+// parseMapping parses header=column pairs. An empty column ignores the header.
+func parseMapping(value string) (map[string]string, error) {
+	mapping := map[string]string{}
+	if value == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid mapping %q, expected header=column", pair)
+		}
+		mapping[parts[0]] = parts[1]
+	}
+
+	return mapping, nil
+}
+
+func readReport(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return bulk.ReadReport(file)
+}
+
+// fileFormat returns format if set, otherwise guesses it from the extension of path.
+func fileFormat(format, path string) bulk.Format {
+	if format != "" {
+		return bulk.Format(format)
+	}
+
+	switch filepath.Ext(path) {
+	case ".ndjson", ".jsonl":
+		return bulk.FormatNDJSON
+	default:
+		return bulk.FormatCSV
+	}
+}
+
+// open opens path for reading, - is stdin.
+func (c *command) open(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return ioutil.NopCloser(c.stdin), nil
+	}
+
+	return os.Open(path)
+}
+
+// create creates path for writing, - is stdout.
+func (c *command) create(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{c.stdout}, nil
+	}
+
+	return os.Create(path)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,152 @@
+// Command form3 inspects and manages Form3 resources from a terminal.
+//
+// Usage:
+//
+//	form3 [-profile NAME] [-base-url URL] [-token TOKEN] [-mode normal|read-only|dry-run] [-output table|json|yaml] accounts <command> [flags]
+//
+// The base URL, token and mode default to the profile of ~/.form3/config, overridden by FORM3_BASE_URL, FORM3_TOKEN
+// and FORM3_MODE environment variables, see package config. Exit codes describe the
+// kind of failure, see the exit* constants.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/ptrsd/form3"
+	"github.com/ptrsd/form3/config"
+)
+
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitNotFound
+	exitConflict
+	exitInvalid
+	exitUnauthorized
+	exitUnavailable
+	exitReadOnly
+)
+
+// errUsage is returned when command line arguments are invalid. Usage has already been printed.
+var errUsage = errors.New("invalid usage")
+
+// command is a single invocation of the tool.
+type command struct {
+	client *form3.Client
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	output string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("form3", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: form3 [flags] accounts <command> [flags]")
+		flags.PrintDefaults()
+	}
+
+	profileName := flags.String("profile", "", "profile of the config file, FORM3_PROFILE or the default profile if not set")
+	baseURL := flags.String("base-url", "", "Form3 API base URL, overrides the profile")
+	token := flags.String("token", "", "bearer token used to authenticate requests, overrides the profile")
+	modeName := flags.String("mode", "", "normal, read-only to reject changes, or dry-run to log them, overrides the profile")
+	output := flags.String("output", "table", "output format: table, json or yaml")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if !isOutputFormat(*output) || flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	profile, err := config.Load(*profileName)
+	if err != nil {
+		fmt.Fprintf(stderr, "form3: %s\n", err.Error())
+		return exitUsage
+	}
+
+	override(&profile.BaseURL, *baseURL)
+	override(&profile.Token, *token)
+	override(&profile.Mode, *modeName)
+
+	client, err := profile.Client()
+	if err != nil {
+		fmt.Fprintf(stderr, "form3: %s\n", err.Error())
+		return exitUsage
+	}
+	client.Logger = log.New(stderr, "form3: ", 0)
+
+	cmd := &command{client: client, stdin: stdin, stdout: stdout, stderr: stderr, output: *output}
+
+	switch flags.Arg(0) {
+	case "accounts":
+		err = cmd.accounts(flags.Args()[1:])
+	default:
+		flags.Usage()
+		return exitUsage
+	}
+
+	if err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, "form3: %s\n", err.Error())
+		}
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+// exitCode maps errors to exit codes, so scripts can tell missing records from conflicts and outages.
+func exitCode(err error) int {
+	if errors.Is(err, errUsage) {
+		return exitUsage
+	}
+	if errors.Is(err, form3.ErrCircuitOpen) {
+		return exitUnavailable
+	}
+	var readOnlyErr *form3.ReadOnlyError
+	if errors.As(err, &readOnlyErr) {
+		return exitReadOnly
+	}
+
+	var apiErr *form3.APIError
+	if !errors.As(err, &apiErr) {
+		return exitError
+	}
+
+	switch {
+	case apiErr.StatusCode == http.StatusNotFound:
+		return exitNotFound
+	case apiErr.StatusCode == http.StatusConflict:
+		return exitConflict
+	case apiErr.StatusCode == http.StatusBadRequest:
+		return exitInvalid
+	case apiErr.StatusCode == http.StatusUnauthorized, apiErr.StatusCode == http.StatusForbidden:
+		return exitUnauthorized
+	case apiErr.StatusCode == http.StatusTooManyRequests, apiErr.StatusCode >= 500:
+		return exitUnavailable
+	default:
+		return exitError
+	}
+}
+
+// override sets the profile field to the flag value, if the flag is set.
+func override(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ptrsd/form3"
+	"github.com/ptrsd/form3/form3test"
+)
+
+const (
+	testAccountID       = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+	testOrganisationID  = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+	otherOrganisationID = "5b438472-e8f7-4ce5-a189-2968e6f8f62e"
+)
+
+// TestMain keeps tests independent of the config file of the user.
+func TestMain(m *testing.M) {
+	os.Setenv("FORM3_CONFIG", os.DevNull)
+	os.Exit(m.Run())
+}
+
+func TestRun_Profile(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	os.Setenv("FORM3_CONFIG", givenFile(t, "profiles:\n  local:\n    base_url: "+server.URL+"\n    mode: read-only\n"))
+	defer os.Setenv("FORM3_CONFIG", os.DevNull)
+
+	t.Run("When profile is selected then client is configured by it", func(t *testing.T) {
+		stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+		code := run([]string{"-profile", "local", "accounts", "create", "-id", testAccountID,
+			"-organisation-id", testOrganisationID, "-country", "GB"}, strings.NewReader(""), &stdout, &stderr)
+
+		thenEqual(t, "ExitCode", exitReadOnly, code)
+		thenEqual(t, "Error", "form3: client is read-only, POST /v1/organisation/accounts was not sent\n", stderr.String())
+	})
+
+	t.Run("When flags are set then they override the profile", func(t *testing.T) {
+		stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+		code := run([]string{"-profile", "local", "-mode", "normal", "accounts", "list"}, strings.NewReader(""), &stdout, &stderr)
+
+		thenEqual(t, "ExitCode", exitOK, code)
+	})
+
+	t.Run("When profile is not configured then usage exit code is returned", func(t *testing.T) {
+		stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+		code := run([]string{"-profile", "staging", "accounts", "list"}, strings.NewReader(""), &stdout, &stderr)
+
+		thenEqual(t, "ExitCode", exitUsage, code)
+		thenEqual(t, "Error", "form3: profile \"staging\" is not configured\n", stderr.String())
+	})
+}
+
+func TestRun_Accounts(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	t.Run("When creating account then it is printed as JSON", func(t *testing.T) {
+		stdout, code := whenRunning(t, server, "", "-output", "json", "accounts", "create",
+			"-id", testAccountID, "-organisation-id", testOrganisationID, "-country", "GB", "-name", "Jane Doe")
+
+		account := form3.Account{}
+		if err := json.Unmarshal([]byte(stdout), &account); err != nil {
+			t.Fatalf("error while decoding output %q, %s", stdout, err.Error())
+		}
+
+		thenEqual(t, "ExitCode", exitOK, code)
+		thenEqual(t, "ID", testAccountID, account.ID)
+		thenEqual(t, "BankAccountName", "Jane Doe", account.Attributes.BankAccountName)
+	})
+
+	t.Run("When creating duplicate account then conflict exit code is returned", func(t *testing.T) {
+		request := `{"id":"` + testAccountID + `","organisation_id":"` + testOrganisationID + `","attributes":{"country":"GB"}}`
+		_, code := whenRunning(t, server, request, "accounts", "create", "-file", "-")
+
+		thenEqual(t, "ExitCode", exitConflict, code)
+	})
+
+	t.Run("When fetching account then it is printed as a table", func(t *testing.T) {
+		stdout, code := whenRunning(t, server, "", "accounts", "fetch", testAccountID)
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		thenEqual(t, "ExitCode", exitOK, code)
+		thenEqual(t, "Lines", 2, len(lines))
+		thenEqual(t, "Header", true, strings.HasPrefix(lines[0], "ID"))
+		thenEqual(t, "Row", true, strings.HasPrefix(lines[1], testAccountID))
+	})
+
+	t.Run("When listing accounts then they are printed as YAML", func(t *testing.T) {
+		stdout, code := whenRunning(t, server, "", "-output", "yaml", "accounts", "list", "-all")
+
+		thenEqual(t, "ExitCode", exitOK, code)
+		thenEqual(t, "Output", true, strings.HasPrefix(stdout, "- id: "+testAccountID+"\n  organisation_id: "))
+	})
+
+	t.Run("When deleting account without version then current version is deleted", func(t *testing.T) {
+		_, code := whenRunning(t, server, "", "accounts", "delete", testAccountID)
+		thenEqual(t, "ExitCode", exitOK, code)
+
+		_, code = whenRunning(t, server, "", "accounts", "fetch", testAccountID)
+		thenEqual(t, "FetchExitCode", exitNotFound, code)
+	})
+
+	t.Run("When mode is read-only or dry-run then changes are not sent", func(t *testing.T) {
+		_, code := whenRunning(t, server, "", "-mode", "read-only", "accounts", "create",
+			"-id", testAccountID, "-organisation-id", testOrganisationID, "-country", "GB")
+		thenEqual(t, "ExitCode", exitReadOnly, code)
+
+		_, code = whenRunning(t, server, "", "-mode", "dry-run", "accounts", "create",
+			"-id", testAccountID, "-organisation-id", testOrganisationID, "-country", "GB")
+		thenEqual(t, "DryRunExitCode", exitOK, code)
+
+		_, code = whenRunning(t, server, "", "accounts", "fetch", testAccountID)
+		thenEqual(t, "FetchExitCode", exitNotFound, code)
+	})
+
+	t.Run("When arguments are missing then usage exit code is returned", func(t *testing.T) {
+		_, code := whenRunning(t, server, "", "accounts", "fetch")
+		thenEqual(t, "ExitCode", exitUsage, code)
+
+		_, code = whenRunning(t, server, "", "-output", "xml", "accounts", "list")
+		thenEqual(t, "OutputExitCode", exitUsage, code)
+	})
+}
+
+func TestRun_AccountsImport(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	input := "account_id,country\n" + testAccountID + ",GB\n" + "not-a-uuid,GB\n"
+
+	t.Run("When importing accounts then report is printed and invalid rows fail the command", func(t *testing.T) {
+		stdout, code := whenRunning(t, server, input, "accounts", "import",
+			"-organisation-id", testOrganisationID, "-map", "account_id=id", "-workers", "1")
+
+		thenEqual(t, "ExitCode", exitError, code)
+		thenEqual(t, "Report", "row,id,status,error\n"+
+			"1,"+testAccountID+",created,\n"+
+			`2,not-a-uuid,invalid,"id: ""not-a-uuid"" is not a UUID"`+"\n", stdout)
+	})
+
+	t.Run("When mapping is invalid then error exit code is returned", func(t *testing.T) {
+		_, code := whenRunning(t, server, input, "accounts", "import", "-map", "account_id")
+		thenEqual(t, "ExitCode", exitError, code)
+	})
+
+	t.Run("When exporting accounts then selected columns are printed", func(t *testing.T) {
+		stdout, code := whenRunning(t, server, "", "accounts", "export", "-columns", "id,organisation_id,country")
+
+		thenEqual(t, "ExitCode", exitOK, code)
+		thenEqual(t, "Output", "id,organisation_id,country\n"+testAccountID+","+testOrganisationID+",GB\n", stdout)
+	})
+
+	t.Run("When exporting accounts then organisation of the client is exported by default", func(t *testing.T) {
+		os.Setenv("FORM3_ORGANISATION_ID", otherOrganisationID)
+		defer os.Unsetenv("FORM3_ORGANISATION_ID")
+
+		stdout, code := whenRunning(t, server, "", "accounts", "export", "-columns", "id")
+
+		thenEqual(t, "ExitCode", exitOK, code)
+		thenEqual(t, "Output", "id\n", stdout)
+	})
+}
+
+func TestRun_AccountsReconcile(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	ledger := "id,organisation_id,country\n" + testAccountID + "," + testOrganisationID + ",GB\n"
+
+	t.Run("When ledger has missing account then it is reported", func(t *testing.T) {
+		stdout, code := whenRunning(t, server, ledger, "accounts", "reconcile")
+
+		thenEqual(t, "ExitCode", exitError, code)
+		thenEqual(t, "Output", "id,kind,field,ledger,api,result\n"+testAccountID+",missing,,,,\n", stdout)
+	})
+
+	t.Run("When applying then missing account is created", func(t *testing.T) {
+		_, code := whenRunning(t, server, ledger, "accounts", "reconcile", "-apply")
+		thenEqual(t, "ExitCode", exitOK, code)
+
+		stdout, code := whenRunning(t, server, ledger, "accounts", "reconcile")
+		thenEqual(t, "ReconciledExitCode", exitOK, code)
+		thenEqual(t, "Output", "id,kind,field,ledger,api,result\n", stdout)
+	})
+
+	t.Run("When ledger rows have no organisation then organisation of the client is used", func(t *testing.T) {
+		os.Setenv("FORM3_ORGANISATION_ID", testOrganisationID)
+		defer os.Unsetenv("FORM3_ORGANISATION_ID")
+
+		stdout, code := whenRunning(t, server, "id,country\n"+testAccountID+",GB\n", "accounts", "reconcile")
+
+		thenEqual(t, "ExitCode", exitOK, code)
+		thenEqual(t, "Output", "id,kind,field,ledger,api,result\n", stdout)
+	})
+}
+
+func TestRun_AccountsApply(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	path := givenFile(t, "organisation_id: "+testOrganisationID+"\naccounts:\n  - id: "+testAccountID+"\n    attributes:\n      country: GB\n")
+
+	t.Run("When planning then diff is printed", func(t *testing.T) {
+		stdout, code := whenRunning(t, server, "", "accounts", "plan", path)
+
+		thenEqual(t, "ExitCode", exitOK, code)
+		thenEqual(t, "Summary", true, strings.HasSuffix(stdout, "Plan: 1 to create, 0 to update, 0 to replace, 0 to delete.\n"))
+	})
+
+	t.Run("When apply is not confirmed then nothing is changed", func(t *testing.T) {
+		_, code := whenRunning(t, server, "no\n", "accounts", "apply", path)
+
+		thenEqual(t, "ExitCode", exitError, code)
+		thenEqual(t, "Resources", 0, len(server.Resources(form3test.AccountsPath)))
+	})
+
+	t.Run("When apply is confirmed then changes are applied", func(t *testing.T) {
+		stdout, code := whenRunning(t, server, "yes\n", "accounts", "apply", path)
+
+		thenEqual(t, "ExitCode", exitOK, code)
+		thenEqual(t, "Applied", true, strings.HasSuffix(stdout, "1 of 1 changes applied.\n"))
+
+		stdout, _ = whenRunning(t, server, "", "accounts", "plan", path)
+		thenEqual(t, "Plan", "Plan: 0 to create, 0 to update, 0 to replace, 0 to delete.\n", stdout)
+	})
+}
+
+func TestExitCode(t *testing.T) {
+	t.Run("When circuit breaker is open then unavailable exit code is returned", func(t *testing.T) {
+		err := fmt.Errorf("GET /v1/organisation/accounts: %w", form3.ErrCircuitOpen)
+		thenEqual(t, "ExitCode", exitUnavailable, exitCode(err))
+	})
+
+	t.Run("When client is read-only then read-only exit code is returned", func(t *testing.T) {
+		err := fmt.Errorf("error while creating account, %w",
+			&form3.ReadOnlyError{Method: "POST", Path: "/v1/organisation/accounts"})
+		thenEqual(t, "ExitCode", exitReadOnly, exitCode(err))
+	})
+}
+
+func givenFile(t *testing.T, content string) string {
+	t.Helper()
+
+	file, err := ioutil.TempFile("", "form3-*.yaml")
+	if err != nil {
+		t.Fatalf("error while creating file, %s", err.Error())
+	}
+	t.Cleanup(func() { os.Remove(file.Name()) })
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatalf("error while writing file, %s", err.Error())
+	}
+
+	return file.Name()
+}
+
+func whenRunning(t *testing.T, server *form3test.Server, stdin string, args ...string) (string, int) {
+	t.Helper()
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	code := run(append([]string{"-base-url", server.URL}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	if code != exitOK {
+		t.Logf("stderr: %s", stderr.String())
+	}
+
+	return stdout.String(), code
+}
+
+func thenEqual(t *testing.T, name string, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s:\nExpected: %#v\n  Actual: %#v", name, expected, actual)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ptrsd/form3/manifest"
+)
+
+func (c *command) planAccounts(args []string) error {
+	flags := c.flagSet("accounts plan", "<manifest>")
+	if err := c.parse(flags, args, 1); err != nil {
+		return err
+	}
+
+	plan, err := c.plan(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return plan.WriteDiff(c.stdout)
+}
+
+func (c *command) applyAccounts(args []string) error {
+	flags := c.flagSet("accounts apply", "[flags] <manifest>")
+	autoApprove := flags.Bool("auto-approve", false, "apply without asking for confirmation")
+
+	if err := c.parse(flags, args, 1); err != nil {
+		return err
+	}
+
+	plan, err := c.plan(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if err := plan.WriteDiff(c.stdout); err != nil {
+		return err
+	}
+
+	if plan.IsEmpty() {
+		return nil
+	}
+
+	if !*autoApprove {
+		fmt.Fprint(c.stdout, "Apply these changes? Only 'yes' will be accepted: ")
+		answer, err := bufio.NewReader(c.stdin).ReadString('\n')
+		if strings.TrimSpace(answer) != "yes" {
+			if err != nil && answer == "" {
+				return fmt.Errorf("apply cancelled, %w", err)
+			}
+			return errors.New("apply cancelled")
+		}
+	}
+
+	applied, err := plan.Apply(c.client.AccountService)
+	fmt.Fprintf(c.stdout, "%d of %d changes applied.\n", applied, len(plan.Changes))
+
+	return err
+}
+
+func (c *command) plan(path string) (manifest.Plan, error) {
+	m, err := manifest.ReadFile(path)
+	if err != nil {
+		return manifest.Plan{}, err
+	}
+
+	return manifest.NewPlan(c.client.AccountService, m)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ptrsd/form3"
+	"gopkg.in/yaml.v2"
+)
+
+var outputFormats = map[string]bool{"table": true, "json": true, "yaml": true}
+
+var accountColumns = []string{"ID", "ORGANISATION ID", "VERSION", "COUNTRY", "BANK ID", "ACCOUNT NUMBER", "IBAN", "NAME"}
+
+func isOutputFormat(format string) bool {
+	return outputFormats[format]
+}
+
+// printAccounts prints a single account.
+func (c *command) printAccounts(account form3.Account) error {
+	if c.output == "table" {
+		return c.printAccountTable([]form3.Account{account})
+	}
+
+	return c.print(account)
+}
+
+// printAccountList prints a list of accounts. Empty lists are printed as empty arrays rather than null.
+func (c *command) printAccountList(accounts []form3.Account) error {
+	if accounts == nil {
+		accounts = []form3.Account{}
+	}
+
+	if c.output == "table" {
+		return c.printAccountTable(accounts)
+	}
+
+	return c.print(accounts)
+}
+
+func (c *command) printAccountTable(accounts []form3.Account) error {
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	printRow(table, accountColumns)
+
+	for _, account := range accounts {
+		printRow(table, []string{
+			account.ID,
+			account.OrganisationID,
+			strconv.Itoa(account.Version),
+			account.Attributes.Country,
+			account.Attributes.BankID,
+			account.Attributes.AccountNumber,
+			account.Attributes.Iban,
+			account.Attributes.BankAccountName,
+		})
+	}
+
+	return table.Flush()
+}
+
+// print writes v as indented JSON or as YAML with the same field names and order as JSON.
+func (c *command) print(v interface{}) error {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if c.output == "json" {
+		_, err = fmt.Fprintln(c.stdout, string(raw))
+		return err
+	}
+
+	var ordered interface{} = &yaml.MapSlice{}
+	if len(raw) > 0 && raw[0] == '[' {
+		ordered = &[]yaml.MapSlice{}
+	}
+
+	if err := yaml.Unmarshal(raw, ordered); err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(ordered)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.stdout.Write(out)
+	return err
+}
+
+func printRow(table *tabwriter.Writer, columns []string) {
+	for idx, column := range columns {
+		if idx > 0 {
+			fmt.Fprint(table, "\t")
+		}
+		fmt.Fprint(table, column)
+	}
+	fmt.Fprintln(table)
+}
@@ -0,0 +1,339 @@
+// Package config loads named profiles of Form3 clients from ~/.form3/config and FORM3_* environment variables. The
+// config is YAML or TOML, chosen by the extension of the file or by its content.
+//
+//	default_profile: local
+//	profiles:
+//	  local:
+//	    base_url: http://localhost:8080
+//	  production:
+//	    base_url: https://api.form3.tech
+//	    organisation_id: eb0bd6f5-c3f5-44b2-b677-acd23cdde73c
+//	    signing_key: ~/.form3/production.pem
+//	    signing_key_id: 75a8ba12-fff2-4a52-ad8a-e8b34c5ccec8
+//	    vault_path: secret/form3
+//	    vault_kv_version: 2
+//	    timeout: 30s
+//	    mode: read-only
+//
+// The same config in TOML:
+//
+//	default_profile = "local"
+//
+//	[profiles.local]
+//	base_url = "http://localhost:8080"
+//
+//	[profiles.production]
+//	base_url = "https://api.form3.tech"
+//	vault_kv_version = 2
+//	timeout = "30s"
+//
+// Environment variables override fields of the selected profile: FORM3_BASE_URL, FORM3_ORGANISATION_ID, FORM3_TOKEN,
+// FORM3_TOKEN_FILE, FORM3_VAULT_PATH, FORM3_VAULT_FIELD, FORM3_VAULT_KV_VERSION, FORM3_TIMEOUT, FORM3_SIGNING_KEY,
+// FORM3_SIGNING_KEY_ID and FORM3_MODE. FORM3_PROFILE selects the profile and FORM3_CONFIG the config file. Vault is
+// addressed by VAULT_ADDR and VAULT_TOKEN.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ptrsd/form3"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultProfile is used when neither the caller, FORM3_PROFILE nor the config file selects a profile.
+	DefaultProfile = "default"
+	// DefaultBaseURL is the base URL of profiles which do not set one.
+	DefaultBaseURL = "http://localhost:8080"
+)
+
+// Config holds named profiles.
+type Config struct {
+	// DefaultProfile is the profile used when none is selected.
+	DefaultProfile string             `yaml:"default_profile" toml:"default_profile"`
+	Profiles       map[string]Profile `yaml:"profiles" toml:"profiles"`
+}
+
+// Profile configures a client of a single environment.
+type Profile struct {
+	BaseURL string `yaml:"base_url" toml:"base_url"`
+	// OrganisationID is the default organisation of the client, see form3.Client.OrganisationID.
+	OrganisationID string `yaml:"organisation_id" toml:"organisation_id"`
+	// Token is a bearer token sent with every request, unless TokenFile or VaultPath is set.
+	Token string `yaml:"token" toml:"token"`
+	// TokenFile is a path of a file holding the token. It is read again when the server rejects the token.
+	TokenFile string `yaml:"token_file" toml:"token_file"`
+	// VaultPath is a path of a Vault KV secret holding the token, e.g. secret/form3. It is read again when its lease
+	// expires or the server rejects the token.
+	VaultPath string `yaml:"vault_path" toml:"vault_path"`
+	// VaultField is the field of the secret holding the token, token by default.
+	VaultField string `yaml:"vault_field" toml:"vault_field"`
+	// VaultKVVersion is the version of the KV secrets engine mounted at VaultPath, 1 or 2. Version 1 if not set.
+	VaultKVVersion int `yaml:"vault_kv_version" toml:"vault_kv_version"`
+	// Timeout limits every request, zero for no limit.
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"`
+	// SigningKey is a path of a PEM encoded RSA private key which signs requests. A leading ~ is the home directory.
+	SigningKey string `yaml:"signing_key" toml:"signing_key"`
+	// SigningKeyID is the ID of the public key of SigningKey registered in Form3.
+	SigningKeyID string `yaml:"signing_key_id" toml:"signing_key_id"`
+	// Mode is normal, read-only or dry-run, see form3.ParseMode.
+	Mode string `yaml:"mode" toml:"mode"`
+}
+
+// Path returns the path of the config file: FORM3_CONFIG, or ~/.form3/config.
+func Path() (string, error) {
+	if path := os.Getenv("FORM3_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".form3", "config"), nil
+}
+
+// Read reads a config in YAML or TOML, telling them apart by the first statement.
+func Read(r io.Reader) (Config, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if isTOML(raw) {
+		return readTOML(raw)
+	}
+
+	return readYAML(raw)
+}
+
+func readYAML(raw []byte) (Config, error) {
+	config := Config{}
+	if err := yaml.UnmarshalStrict(raw, &config); err != nil {
+		return Config{}, fmt.Errorf("error while parsing config, %w", err)
+	}
+
+	return config, nil
+}
+
+// readTOML decodes TOML into the fields read from YAML. Unknown fields are rejected like in YAML.
+func readTOML(raw []byte) (Config, error) {
+	config := Config{}
+	metadata, err := toml.Decode(string(raw), &config)
+	if err != nil {
+		return Config{}, fmt.Errorf("error while parsing config, %w", err)
+	}
+
+	if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+		return Config{}, fmt.Errorf("error while parsing config, unknown field %s", undecoded[0].String())
+	}
+
+	return config, nil
+}
+
+// isTOML tells whether the first statement of the config is a TOML table or key = value pair rather than YAML.
+func isTOML(raw []byte) bool {
+	for _, line := range strings.Split(string(raw), "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || line == "---" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			return true
+		}
+
+		equals, colon := strings.Index(line, "="), strings.Index(line, ":")
+		return equals >= 0 && (colon < 0 || equals < colon)
+	}
+
+	return false
+}
+
+// ReadFile reads a config file. Files with .toml, .yaml or .yml extensions are read in that format, others are told
+// apart by their content. A file which does not exist is an empty config.
+func ReadFile(path string) (Config, error) {
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	var config Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		config, err = readTOML(raw)
+	case ".yaml", ".yml":
+		config, err = readYAML(raw)
+	default:
+		config, err = Read(bytes.NewReader(raw))
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Load reads the config file and returns the named profile with environment variables applied. An empty name selects
+// FORM3_PROFILE, then the default profile of the config.
+func Load(name string) (Profile, error) {
+	path, err := Path()
+	if err != nil {
+		return Profile{}, err
+	}
+
+	config, err := ReadFile(path)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	if name == "" {
+		name = os.Getenv("FORM3_PROFILE")
+	}
+
+	profile, err := config.Profile(name)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	return profile.Override(os.LookupEnv)
+}
+
+// Profile returns the named profile, or the default profile if the name is empty. Only the default profile may be
+// missing from the config, it is empty then.
+func (c Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok && name != DefaultProfile && name != c.DefaultProfile {
+		return Profile{}, fmt.Errorf("profile %q is not configured", name)
+	}
+
+	return profile, nil
+}
+
+// Override replaces fields of the profile with FORM3_* variables found by lookup, e.g. os.LookupEnv.
+func (p Profile) Override(lookup func(string) (string, bool)) (Profile, error) {
+	fields := map[string]*string{
+		"FORM3_BASE_URL":        &p.BaseURL,
+		"FORM3_ORGANISATION_ID": &p.OrganisationID,
+		"FORM3_TOKEN":           &p.Token,
+		"FORM3_TOKEN_FILE":      &p.TokenFile,
+		"FORM3_VAULT_PATH":      &p.VaultPath,
+		"FORM3_VAULT_FIELD":     &p.VaultField,
+		"FORM3_SIGNING_KEY":     &p.SigningKey,
+		"FORM3_SIGNING_KEY_ID":  &p.SigningKeyID,
+		"FORM3_MODE":            &p.Mode,
+	}
+	for key, field := range fields {
+		if value, ok := lookup(key); ok && value != "" {
+			*field = value
+		}
+	}
+
+	if value, ok := lookup("FORM3_TIMEOUT"); ok && value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return Profile{}, fmt.Errorf("FORM3_TIMEOUT: %w", err)
+		}
+		p.Timeout = timeout
+	}
+
+	if value, ok := lookup("FORM3_VAULT_KV_VERSION"); ok && value != "" {
+		version, err := strconv.Atoi(value)
+		if err != nil {
+			return Profile{}, fmt.Errorf("FORM3_VAULT_KV_VERSION: %w", err)
+		}
+		p.VaultKVVersion = version
+	}
+
+	return p, nil
+}
+
+// Client builds a client of the profile.
+func (p Profile) Client() (*form3.Client, error) {
+	mode, err := form3.ParseMode(p.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := p.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	client := form3.NewClient(&http.Client{Timeout: p.Timeout}, baseURL)
+	client.OrganisationID = p.OrganisationID
+	client.Token = p.Token
+	client.Mode = mode
+
+	switch {
+	case p.VaultPath != "":
+		if p.VaultKVVersion < 0 || p.VaultKVVersion > 2 {
+			return nil, fmt.Errorf("vault KV version %d is not supported, expected 1 or 2", p.VaultKVVersion)
+		}
+		client.Credentials = &form3.CachedCredentials{Provider: &form3.VaultCredentials{
+			Path:      p.VaultPath,
+			Field:     p.VaultField,
+			KVVersion: p.VaultKVVersion,
+		}}
+	case p.TokenFile != "":
+		path, err := expandHome(p.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		client.Credentials = &form3.CachedCredentials{Provider: form3.FileCredentials{Path: path}}
+	}
+
+	if (p.SigningKey == "") != (p.SigningKeyID == "") {
+		return nil, fmt.Errorf("signing_key and signing_key_id must be set together")
+	}
+
+	if p.SigningKey != "" {
+		path, err := expandHome(p.SigningKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if client.Signer, err = form3.LoadRSASigner(p.SigningKeyID, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
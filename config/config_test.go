@@ -0,0 +1,309 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ptrsd/form3"
+)
+
+const testConfig = `
+default_profile: local
+profiles:
+  local:
+    base_url: http://localhost:8080
+  production:
+    base_url: https://api.form3.tech
+    organisation_id: eb0bd6f5-c3f5-44b2-b677-acd23cdde73c
+    token: secret
+    timeout: 30s
+    mode: read-only
+`
+
+const testTOMLConfig = `
+# Profiles of Form3 clients.
+default_profile = "local"
+profiles.local.base_url = "http://localhost:8080"
+
+[profiles.production]
+base_url = "https://api.form3.tech" # public API
+organisation_id = 'eb0bd6f5-c3f5-44b2-b677-acd23cdde73c'
+token = "se#cret\u00e9"
+vault_kv_version = 2
+timeout = "30s"
+mode = """read-only"""
+`
+
+func TestRead_TOML(t *testing.T) {
+	t.Run("When config is TOML then profiles are read like in YAML", func(t *testing.T) {
+		config, err := Read(strings.NewReader(testTOMLConfig))
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Config", Config{
+			DefaultProfile: "local",
+			Profiles: map[string]Profile{
+				"local": {BaseURL: "http://localhost:8080"},
+				"production": {
+					BaseURL:        "https://api.form3.tech",
+					OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+					Token:          "se#cret\u00e9",
+					VaultKVVersion: 2,
+					Timeout:        30 * time.Second,
+					Mode:           "read-only",
+				},
+			},
+		}, config)
+	})
+
+	t.Run("When TOML is invalid then error is returned", func(t *testing.T) {
+		_, err := Read(strings.NewReader("[profiles.local]\nbase_url"))
+		thenEqual(t, "Error", true, err != nil)
+	})
+
+	t.Run("When TOML has unknown fields then error is returned", func(t *testing.T) {
+		_, err := Read(strings.NewReader("[profiles.local]\nbase-url = \"http://localhost:8080\""))
+		thenEqual(t, "Error", "error while parsing config, unknown field profiles.local.base-url", errorMessage(err))
+	})
+}
+
+func TestReadFile_Format(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("error while creating directory, %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name   string
+		file   string
+		config string
+	}{
+		{name: "When file has toml extension then it is read as TOML", file: "config.toml", config: testTOMLConfig},
+		{name: "When file has yaml extension then it is read as YAML", file: "config.yaml", config: testConfig},
+		{name: "When file has no extension then TOML is told by content", file: "toml", config: testTOMLConfig},
+		{name: "When file has no extension then YAML is told by content", file: "yaml", config: testConfig},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, test.file)
+			if err := ioutil.WriteFile(path, []byte(test.config), 0600); err != nil {
+				t.Fatalf("error while writing config, %s", err.Error())
+			}
+
+			config, err := ReadFile(path)
+
+			thenEqual(t, "Error", nil, err)
+			thenEqual(t, "BaseURL", "https://api.form3.tech", config.Profiles["production"].BaseURL)
+		})
+	}
+
+	t.Run("When YAML file has toml extension then error is returned", func(t *testing.T) {
+		path := filepath.Join(dir, "yaml.toml")
+		if err := ioutil.WriteFile(path, []byte(testConfig), 0600); err != nil {
+			t.Fatalf("error while writing config, %s", err.Error())
+		}
+
+		_, err := ReadFile(path)
+		thenEqual(t, "Error", true, err != nil)
+	})
+}
+
+func TestConfig_Profile(t *testing.T) {
+	config, err := Read(strings.NewReader(testConfig))
+	thenEqual(t, "Error", nil, err)
+
+	t.Run("When profile is not named then default profile is returned", func(t *testing.T) {
+		profile, err := config.Profile("")
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "BaseURL", "http://localhost:8080", profile.BaseURL)
+	})
+
+	t.Run("When profile is named then its fields are returned", func(t *testing.T) {
+		profile, err := config.Profile("production")
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Profile", Profile{
+			BaseURL:        "https://api.form3.tech",
+			OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+			Token:          "secret",
+			Timeout:        30 * time.Second,
+			Mode:           "read-only",
+		}, profile)
+	})
+
+	t.Run("When profile is not configured then error is returned", func(t *testing.T) {
+		_, err := config.Profile("staging")
+		thenEqual(t, "Error", `profile "staging" is not configured`, errorMessage(err))
+
+		_, err = Config{}.Profile("")
+		thenEqual(t, "EmptyConfigError", nil, err)
+	})
+
+	t.Run("When config has unknown fields then error is returned", func(t *testing.T) {
+		_, err := Read(strings.NewReader("profiles:\n  local:\n    base-url: http://localhost:8080\n"))
+		thenEqual(t, "Error", true, err != nil)
+	})
+}
+
+func TestProfile_Override(t *testing.T) {
+	env := map[string]string{"FORM3_BASE_URL": "http://accountapi:8080", "FORM3_TIMEOUT": "5s", "FORM3_MODE": ""}
+	lookup := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	profile, err := Profile{BaseURL: "http://localhost:8080", Mode: "dry-run"}.Override(lookup)
+
+	thenEqual(t, "Error", nil, err)
+	thenEqual(t, "Profile", Profile{BaseURL: "http://accountapi:8080", Timeout: 5 * time.Second, Mode: "dry-run"}, profile)
+
+	env["FORM3_TIMEOUT"] = "5"
+	_, err = Profile{}.Override(lookup)
+	thenEqual(t, "InvalidTimeout", true, strings.HasPrefix(errorMessage(err), "FORM3_TIMEOUT: "))
+
+	env = map[string]string{"FORM3_VAULT_KV_VERSION": "2"}
+	profile, err = Profile{VaultPath: "secret/form3"}.Override(lookup)
+	thenEqual(t, "Error", nil, err)
+	thenEqual(t, "VaultKVVersion", 2, profile.VaultKVVersion)
+
+	env["FORM3_VAULT_KV_VERSION"] = "v2"
+	_, err = Profile{}.Override(lookup)
+	thenEqual(t, "InvalidVaultKVVersion", true, strings.HasPrefix(errorMessage(err), "FORM3_VAULT_KV_VERSION: "))
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("error while creating directory, %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(testConfig), 0600); err != nil {
+		t.Fatalf("error while writing config, %s", err.Error())
+	}
+
+	givenEnv(t, "FORM3_CONFIG", path)
+	givenEnv(t, "FORM3_PROFILE", "production")
+	givenEnv(t, "FORM3_TOKEN", "rotated")
+
+	t.Run("When profile is selected by environment then client is configured by it", func(t *testing.T) {
+		profile, err := Load("")
+		if err != nil {
+			t.Fatalf("error while loading profile, %s", err.Error())
+		}
+
+		client, err := profile.Client()
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "BaseURL", "https://api.form3.tech", client.BaseURL.String())
+		thenEqual(t, "OrganisationID", "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c", client.OrganisationID)
+		thenEqual(t, "Token", "rotated", client.Token)
+		thenEqual(t, "Mode", form3.ModeReadOnly, client.Mode)
+	})
+
+	t.Run("When profile is named then it takes precedence over environment", func(t *testing.T) {
+		profile, err := Load("local")
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "BaseURL", "http://localhost:8080", profile.BaseURL)
+	})
+
+	t.Run("When config file does not exist then default profile is empty", func(t *testing.T) {
+		givenEnv(t, "FORM3_CONFIG", filepath.Join(dir, "missing"))
+		givenEnv(t, "FORM3_PROFILE", "")
+
+		profile, err := Load("")
+		client, clientErr := profile.Client()
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "ClientError", nil, clientErr)
+		thenEqual(t, "BaseURL", DefaultBaseURL, client.BaseURL.String())
+	})
+
+	t.Run("When token file is set then token is read from it", func(t *testing.T) {
+		tokenPath := filepath.Join(dir, "token")
+		if err := ioutil.WriteFile(tokenPath, []byte("from-file\n"), 0600); err != nil {
+			t.Fatalf("error while writing token, %s", err.Error())
+		}
+
+		client, err := Profile{TokenFile: tokenPath}.Client()
+		if err != nil {
+			t.Fatalf("error while building client, %s", err.Error())
+		}
+
+		credentials, err := client.Credentials.Credentials()
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Token", "from-file", credentials.Token)
+	})
+
+	t.Run("When vault KV version is set then vault credentials use it", func(t *testing.T) {
+		client, err := Profile{VaultPath: "secret/form3", VaultKVVersion: 2}.Client()
+		if err != nil {
+			t.Fatalf("error while building client, %s", err.Error())
+		}
+
+		cached, ok := client.Credentials.(*form3.CachedCredentials)
+		if !ok {
+			t.Fatalf("credentials %T are not cached", client.Credentials)
+		}
+		vault, ok := cached.Provider.(*form3.VaultCredentials)
+		if !ok {
+			t.Fatalf("credentials %T are not read from vault", cached.Provider)
+		}
+		thenEqual(t, "KVVersion", 2, vault.KVVersion)
+
+		_, err = Profile{VaultPath: "secret/form3", VaultKVVersion: 3}.Client()
+		thenEqual(t, "UnsupportedVersionError", "vault KV version 3 is not supported, expected 1 or 2", errorMessage(err))
+	})
+
+	t.Run("When mode is invalid then client is not built", func(t *testing.T) {
+		_, err := Profile{Mode: "careful"}.Client()
+		thenEqual(t, "Error", true, err != nil)
+	})
+
+	t.Run("When signing key or its ID is set without the other then client is not built", func(t *testing.T) {
+		_, err := Profile{SigningKey: "~/.form3/production.pem"}.Client()
+		thenEqual(t, "KeyError", "signing_key and signing_key_id must be set together", errorMessage(err))
+
+		_, err = Profile{SigningKeyID: "75a8ba12-fff2-4a52-ad8a-e8b34c5ccec8"}.Client()
+		thenEqual(t, "KeyIDError", "signing_key and signing_key_id must be set together", errorMessage(err))
+	})
+}
+
+func givenEnv(t *testing.T, key, value string) {
+	t.Helper()
+
+	previous, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+func thenEqual(t *testing.T, name string, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s:\nExpected: %#v\n  Actual: %#v", name, expected, actual)
+	}
+}
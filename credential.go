@@ -0,0 +1,95 @@
+package form3
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+)
+
+const (
+	publicKeyCredentialsPath = "credentials/public_key"
+	publicKeyType            = "public_keys"
+)
+
+// PublicKey describes a public key of a user used to verify signatures of requests.
+type PublicKey struct {
+	// ID is a mandatory, UUID version 4 field. It identifies the key within a system and is used as a key ID of
+	// signatures.
+	ID             string              `json:"id,omitempty"`
+	OrganisationID string              `json:"organisation_id,omitempty"`
+	CreatedOn      string              `json:"created_on,omitempty"`
+	ModifiedOn     string              `json:"modified_on,omitempty"`
+	Type           string              `json:"type,omitempty"`
+	Version        int                 `json:"version,omitempty"`
+	Attributes     PublicKeyAttributes `json:"attributes,omitempty"`
+}
+
+// PublicKeyAttributes describes attributes of a public key.
+type PublicKeyAttributes struct {
+	// PublicKey is a mandatory, PEM encoded public key. Use EncodePublicKey to encode crypto keys.
+	PublicKey   string `json:"public_key,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PublicKeyRequest describes a public key to be uploaded.
+type PublicKeyRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies the key within a system.
+	ID             string              `json:"id,omitempty"`
+	OrganisationID string              `json:"organisation_id"`
+	Type           string              `json:"type,omitempty"`
+	Attributes     PublicKeyAttributes `json:"attributes"`
+}
+
+// CredentialService manages credentials of users. Credentials are sub-resources of a user.
+type CredentialService struct {
+	client *Client
+}
+
+// CreatePublicKey uploads a public key of the user. Requests without an organisation ID are created in
+// Client.OrganisationID. Returns PublicKey or an error for network problem, and for non-2xx server statuses.
+func (c *CredentialService) CreatePublicKey(userID string, createReq PublicKeyRequest) (PublicKey, error) {
+	if createReq.Type == "" {
+		createReq.Type = publicKeyType
+	}
+	if createReq.OrganisationID == "" {
+		createReq.OrganisationID = c.client.OrganisationID
+	}
+
+	publicKey := PublicKey{}
+	err := c.client.createResource(resourcePath(securityUsersBasePath, userID, publicKeyCredentialsPath), createReq, &publicKey)
+
+	return publicKey, err
+}
+
+// FetchPublicKey returns a public key of the user or an error for network problem, and for non-2xx server statuses.
+func (c *CredentialService) FetchPublicKey(userID, id string) (PublicKey, error) {
+	publicKey := PublicKey{}
+	err := c.client.fetchResource(resourcePath(securityUsersBasePath, userID, publicKeyCredentialsPath, id), &publicKey)
+
+	return publicKey, err
+}
+
+// ListPublicKeys lists public keys of the user. Accepts pagination options as an argument. Returns list of keys, true
+// if there are more pages with keys or an error for network problem, and for non-2xx server statuses.
+func (c *CredentialService) ListPublicKeys(userID string, options ListOptions) ([]PublicKey, bool, error) {
+	var publicKeys []PublicKey
+	hasNext, err := c.client.listResources(resourcePath(securityUsersBasePath, userID, publicKeyCredentialsPath), pagingQuery(options), &publicKeys)
+
+	return publicKeys, hasNext, err
+}
+
+// DeletePublicKey deletes a public key of the user, so requests signed with the matching private key are rejected.
+// Returns error for network problem, and for non-2xx server statuses.
+func (c *CredentialService) DeletePublicKey(userID, id string, version int) error {
+	return c.client.deleteResource(resourcePath(securityUsersBasePath, userID, publicKeyCredentialsPath, id), version)
+}
+
+// EncodePublicKey encodes a public key, e.g. *rsa.PublicKey, as a PEM block accepted by CreatePublicKey.
+func EncodePublicKey(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
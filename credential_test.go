@@ -0,0 +1,100 @@
+package form3
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestCredentialService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	user, err := givenUser(client, "provisioning")
+	if err != nil {
+		t.Fatalf("create user returned with error %v", err.Error())
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("error while generating key, %s", err.Error())
+	}
+
+	t.Run("When uploading public key then it is stored under the user", func(t *testing.T) {
+		encoded, err := EncodePublicKey(&privateKey.PublicKey)
+		if err != nil {
+			t.Fatalf("error while encoding public key, %s", err.Error())
+		}
+
+		keyID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		created, err := client.CredentialService.CreatePublicKey(user.ID, PublicKeyRequest{
+			ID:             keyID,
+			OrganisationID: user.OrganisationID,
+			Attributes:     PublicKeyAttributes{PublicKey: encoded, Description: "signing key"},
+		})
+		if err != nil {
+			t.Fatalf("create public key returned with error %v", err.Error())
+		}
+
+		keys, _, err := client.CredentialService.ListPublicKeys(user.ID, ListOptions{})
+		if err != nil {
+			t.Fatalf("list public keys returned with error %v", err.Error())
+		}
+
+		block, _ := pem.Decode([]byte(keys[0].Attributes.PublicKey))
+		if block == nil {
+			t.Fatalf("stored public key is not PEM encoded")
+		}
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			t.Fatalf("error while parsing stored public key, %s", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: created.Type, expected: publicKeyType, name: "Type"},
+			{actual: len(keys), expected: 1, name: "Length"},
+			{actual: parsed, expected: &privateKey.PublicKey, name: "PublicKey"},
+		})
+
+		if err := client.CredentialService.DeletePublicKey(user.ID, keyID, created.Version); err != nil {
+			t.Fatalf("delete public key returned with error %v", err.Error())
+		}
+
+		if _, err := client.CredentialService.FetchPublicKey(user.ID, keyID); err == nil {
+			t.Errorf("fetch of deleted public key should return error")
+		}
+	})
+	t.Run("When client is scoped to organisation then public key is created in it", func(t *testing.T) {
+		encoded, err := EncodePublicKey(&privateKey.PublicKey)
+		if err != nil {
+			t.Fatalf("error while encoding public key, %s", err.Error())
+		}
+
+		keyID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		created, err := client.ForOrganisation(user.OrganisationID).CredentialService.CreatePublicKey(user.ID, PublicKeyRequest{
+			ID:         keyID,
+			Attributes: PublicKeyAttributes{PublicKey: encoded},
+		})
+		if err != nil {
+			t.Fatalf("create public key returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: created.OrganisationID, expected: user.OrganisationID, name: "OrganisationID"},
+		})
+	})
+}
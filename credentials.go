@@ -0,0 +1,162 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials authenticate requests with a bearer token.
+type Credentials struct {
+	Token string
+	// ExpiresAt is when the token must be read again, zero if it does not expire.
+	ExpiresAt time.Time
+}
+
+// CredentialProvider reads credentials, e.g. from the environment, a file or a secret store. Set it as
+// Client.Credentials.
+type CredentialProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// invalidator is implemented by providers which cache credentials, see CachedCredentials.
+type invalidator interface {
+	Invalidate()
+}
+
+// CachedCredentials caches credentials of a provider until they expire, or until they are invalidated because the
+// server rejected them. It is safe for concurrent use.
+type CachedCredentials struct {
+	Provider CredentialProvider
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+
+	mu          sync.Mutex
+	credentials *Credentials
+}
+
+// Credentials returns the cached credentials, reading them from the provider if they have expired or have not been
+// read yet.
+func (c *CachedCredentials) Credentials() (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+
+	if c.credentials != nil && (c.credentials.ExpiresAt.IsZero() || now().Before(c.credentials.ExpiresAt)) {
+		return *c.credentials, nil
+	}
+
+	credentials, err := c.Provider.Credentials()
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.credentials = &credentials
+
+	return credentials, nil
+}
+
+// Invalidate drops the cached credentials, so they are read again by the next request.
+func (c *CachedCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.credentials = nil
+}
+
+// EnvCredentials reads a token from an environment variable.
+type EnvCredentials struct {
+	// Variable holds the token, FORM3_TOKEN if empty.
+	Variable string
+}
+
+// Credentials returns the token of the variable, or an error if it is not set.
+func (e EnvCredentials) Credentials() (Credentials, error) {
+	variable := e.Variable
+	if variable == "" {
+		variable = "FORM3_TOKEN"
+	}
+
+	token := os.Getenv(variable)
+	if token == "" {
+		return Credentials{}, fmt.Errorf("%s is not set", variable)
+	}
+
+	return Credentials{Token: token}, nil
+}
+
+// FileCredentials reads a token from a file, e.g. a mounted Kubernetes secret. Surrounding white space is ignored.
+type FileCredentials struct {
+	Path string
+}
+
+// Credentials returns the token of the file, or an error if it cannot be read or is empty.
+func (f FileCredentials) Credentials() (Credentials, error) {
+	raw, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	token := strings.TrimSpace(string(raw))
+	if token == "" {
+		return Credentials{}, fmt.Errorf("%s is empty", f.Path)
+	}
+
+	return Credentials{Token: token}, nil
+}
+
+// authorize authenticates the request with credentials of the provider, or with Token. The request is signed when it
+// is transmitted.
+func (c *Client) authorize(req *http.Request) error {
+	token := c.Token
+	if c.Credentials != nil {
+		credentials, err := c.Credentials.Credentials()
+		if err != nil {
+			return fmt.Errorf("error while reading credentials, %w", err)
+		}
+		token = credentials.Token
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return nil
+}
+
+// rejected tells whether the server rejected credentials of the provider, which may have been rotated since.
+func (c *Client) rejected(resp *http.Response) bool {
+	return c.Credentials != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden)
+}
+
+// reauthorize invalidates cached credentials and returns a copy of the request authorized with fresh ones.
+func (c *Client) reauthorize(req *http.Request) (*http.Request, error) {
+	if cache, ok := c.Credentials.(invalidator); ok {
+		cache.Invalidate()
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	} else if req.Body != nil && req.Body != http.NoBody {
+		return nil, errors.New("request body cannot be sent again")
+	}
+
+	if err := c.authorize(retry); err != nil {
+		return nil, err
+	}
+
+	return retry, nil
+}
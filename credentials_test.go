@@ -0,0 +1,164 @@
+package form3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestCredentialProviders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatalf("error while creating directory, %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	t.Run("When variable is set then its token is returned", func(t *testing.T) {
+		os.Setenv("FORM3_TEST_TOKEN", "secret")
+		defer os.Unsetenv("FORM3_TEST_TOKEN")
+
+		credentials, err := EnvCredentials{Variable: "FORM3_TEST_TOKEN"}.Credentials()
+		_, missingErr := EnvCredentials{Variable: "FORM3_TEST_MISSING"}.Credentials()
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: credentials, expected: Credentials{Token: "secret"}, name: "Credentials"},
+			{actual: missingErr.Error(), expected: "FORM3_TEST_MISSING is not set", name: "MissingError"},
+		})
+	})
+
+	t.Run("When file holds token then it is returned without white space", func(t *testing.T) {
+		path := filepath.Join(dir, "token")
+		givenToken(t, path, "  secret\n")
+
+		credentials, err := FileCredentials{Path: path}.Credentials()
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: credentials.Token, expected: "secret", name: "Token"},
+		})
+	})
+
+	t.Run("When cached credentials expire then they are read again", func(t *testing.T) {
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		provider := &countingProvider{expiresIn: time.Minute, now: func() time.Time { return now }}
+		cache := &CachedCredentials{Provider: provider, Now: func() time.Time { return now }}
+
+		_, _ = cache.Credentials()
+		now = now.Add(59 * time.Second)
+		_, _ = cache.Credentials()
+		thenEquals(t, assertions{
+			{actual: provider.reads, expected: 1, name: "BeforeExpiry"},
+		})
+
+		now = now.Add(time.Second)
+		_, _ = cache.Credentials()
+		cache.Invalidate()
+		_, _ = cache.Credentials()
+		thenEquals(t, assertions{
+			{actual: provider.reads, expected: 3, name: "AfterExpiry"},
+		})
+	})
+}
+
+func TestClient_Credentials(t *testing.T) {
+	fake := form3test.NewServer()
+	defer fake.Close()
+
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatalf("error while creating directory, %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	validToken, requests := "old", 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer "+validToken {
+			form3test.WriteError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		fake.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(dir, "token")
+	givenToken(t, path, "old")
+
+	client := NewClient(nil, server.URL)
+	client.Credentials = &CachedCredentials{Provider: FileCredentials{Path: path}}
+
+	t.Run("When token is rotated then request is sent again with the new token", func(t *testing.T) {
+		_, _, err := client.AccountService.List(ListOptions{})
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "OldToken.Error"},
+		})
+
+		validToken = "new"
+		givenToken(t, path, "new")
+		requests = 0
+
+		req, err := generateAccountWithAttributes(AccountAttributes{Country: "GB"})
+		if err != nil {
+			t.Fatalf("error while generating account request, %s", err.Error())
+		}
+		account, err := client.AccountService.Create(req)
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "NewToken.Error"},
+			{actual: account.ID, expected: req.ID, name: "NewToken.ID"},
+			{actual: requests, expected: 2, name: "Requests"},
+		})
+	})
+
+	t.Run("When new token is rejected too then error is returned", func(t *testing.T) {
+		validToken = "newest"
+		requests = 0
+
+		_, _, err := client.AccountService.List(ListOptions{})
+
+		thenEquals(t, assertions{
+			{actual: hasStatusCode(err, http.StatusUnauthorized), expected: true, name: "Unauthorized"},
+			{actual: requests, expected: 2, name: "Requests"},
+		})
+	})
+
+	t.Run("When credentials cannot be read then request is not sent", func(t *testing.T) {
+		requests = 0
+		client := NewClient(nil, server.URL)
+		client.Credentials = FileCredentials{Path: filepath.Join(dir, "missing")}
+
+		_, _, err := client.AccountService.List(ListOptions{})
+
+		thenEquals(t, assertions{
+			{actual: err != nil, expected: true, name: "Error"},
+			{actual: requests, expected: 0, name: "Requests"},
+		})
+	})
+}
+
+// countingProvider returns tokens expiring after expiresIn and counts reads.
+type countingProvider struct {
+	expiresIn time.Duration
+	now       func() time.Time
+	reads     int
+}
+
+func (p *countingProvider) Credentials() (Credentials, error) {
+	p.reads++
+	return Credentials{Token: "secret", ExpiresAt: p.now().Add(p.expiresIn)}, nil
+}
+
+func givenToken(t *testing.T, path, token string) {
+	t.Helper()
+
+	if err := ioutil.WriteFile(path, []byte(token), 0600); err != nil {
+		t.Fatalf("error while writing token, %s", err.Error())
+	}
+}
@@ -0,0 +1,33 @@
+package form3
+
+import (
+	"errors"
+	"net/http"
+)
+
+// APIError is returned for non-2xx server statuses.
+type APIError struct {
+	// StatusCode is an HTTP status code of the response.
+	StatusCode int
+	// Message is an error message reported by the server, or the status if the server did not report any.
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// IsNotFound returns true if err is an APIError for a record which does not exist.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsConflict returns true if err is an APIError for a duplicate or a version conflict.
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
@@ -0,0 +1,233 @@
+// Package fault injects failures into HTTP clients, so code using the Form3 API can be tested against slow and flaky
+// servers.
+//
+//	transport := &fault.Transport{Script: []fault.Fault{fault.Reset(), fault.Status(503, ""), {}}}
+//	client := form3.NewClient(&http.Client{Transport: transport}, baseURL)
+package fault
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault describes failures injected into a single request. The zero Fault passes the request through unchanged.
+// Latency is applied first, then the request fails with a reset, is answered with a status or is forwarded with its
+// response body optionally truncated.
+type Fault struct {
+	// Latency delays the request.
+	Latency time.Duration
+	// Reset fails the request with a connection reset before it is sent.
+	Reset bool
+	// StatusCode answers the request with this status instead of forwarding it.
+	StatusCode int
+	// Body of StatusCode responses.
+	Body string
+	// ContentType of StatusCode responses, text/plain by default.
+	ContentType string
+	// Truncate cuts the forwarded response body in half, reading it then fails with io.ErrUnexpectedEOF.
+	Truncate bool
+}
+
+// Latency delays a request by d.
+func Latency(d time.Duration) Fault {
+	return Fault{Latency: d}
+}
+
+// Reset fails a request with a connection reset.
+func Reset() Fault {
+	return Fault{Reset: true}
+}
+
+// Status answers a request with the status and body.
+func Status(statusCode int, body string) Fault {
+	return Fault{StatusCode: statusCode, Body: body}
+}
+
+// NonJSONError answers a request with the status and an HTML body, as proxies and load balancers do.
+func NonJSONError(statusCode int) Fault {
+	return Fault{
+		StatusCode:  statusCode,
+		Body:        "<html><body><h1>" + http.StatusText(statusCode) + "</h1></body></html>",
+		ContentType: "text/html",
+	}
+}
+
+// TruncatedBody forwards a request and truncates its response body.
+func TruncatedBody() Fault {
+	return Fault{Truncate: true}
+}
+
+// Rule injects a fault into matching requests with a probability.
+type Rule struct {
+	Fault
+	// Probability of injecting the fault into a matching request, from 0 to 1.
+	Probability float64
+	// Match selects requests, all requests if nil.
+	Match func(*http.Request) bool
+}
+
+// Transport is an http.RoundTripper injecting faults. Faults of Script are injected into consecutive requests; once
+// the script is exhausted, the first of Rules which matches and wins its draw applies.
+type Transport struct {
+	// Transport sends forwarded requests, http.DefaultTransport if nil.
+	Transport http.RoundTripper
+	// Script lists faults of consecutive requests.
+	Script []Fault
+	// Rules inject faults by probability after the script is exhausted.
+	Rules []Rule
+	// Rand draws probabilities of Rules. Seed it for reproducible runs, a time seeded source is used if nil.
+	Rand *rand.Rand
+	// Sleep waits for latencies, a timer cancelled with the request context if nil.
+	Sleep func(req *http.Request, d time.Duration) error
+
+	mu    sync.Mutex
+	calls int
+}
+
+// RoundTrip sends the request with the next fault injected.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := t.next(req)
+
+	if f.Latency > 0 {
+		sleep := t.Sleep
+		if sleep == nil {
+			sleep = contextSleep
+		}
+		if err := sleep(req, f.Latency); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.Reset {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	}
+
+	if f.StatusCode != 0 {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return f.response(req), nil
+	}
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil || !f.Truncate {
+		return resp, err
+	}
+
+	resp.Body, err = truncate(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.ContentLength = -1
+
+	return resp, nil
+}
+
+// Calls returns the number of requests sent through the transport.
+func (t *Transport) Calls() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.calls
+}
+
+func (t *Transport) next(req *http.Request) Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.calls++
+	if t.calls <= len(t.Script) {
+		return t.Script[t.calls-1]
+	}
+
+	for _, rule := range t.Rules {
+		if rule.Match != nil && !rule.Match(req) {
+			continue
+		}
+
+		if t.Rand == nil {
+			t.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		if t.Rand.Float64() < rule.Probability {
+			return rule.Fault
+		}
+	}
+
+	return Fault{}
+}
+
+func (f Fault) response(req *http.Request) *http.Response {
+	contentType := f.ContentType
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {contentType}},
+		Body:          ioutil.NopCloser(strings.NewReader(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}
+}
+
+// MatchPath returns a Match function selecting requests by method and path prefix. An empty method matches any.
+func MatchPath(method, pathPrefix string) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		return (method == "" || req.Method == method) && strings.HasPrefix(req.URL.Path, pathPrefix)
+	}
+}
+
+func contextSleep(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// truncate reads the body and returns a reader of its first half which fails with io.ErrUnexpectedEOF.
+func truncate(body io.ReadCloser) (io.ReadCloser, error) {
+	defer body.Close()
+
+	raw, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(io.MultiReader(bytes.NewReader(raw[:len(raw)/2]), errReader{io.ErrUnexpectedEOF})), nil
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,99 @@
+package fault
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"1"}}`))
+	}))
+	defer server.Close()
+
+	t.Run("When script is set then faults are injected in order", func(t *testing.T) {
+		var slept time.Duration
+		transport := &Transport{
+			Script: []Fault{Latency(time.Second), Reset(), NonJSONError(http.StatusBadGateway), TruncatedBody()},
+			Sleep:  func(req *http.Request, d time.Duration) error { slept += d; return nil },
+		}
+		client := &http.Client{Transport: transport}
+
+		status, body, err := whenGetting(client, server.URL)
+		thenEqual(t, "LatencyStatus", http.StatusOK, status)
+		thenEqual(t, "LatencyBody", `{"data":{"id":"1"}}`, body)
+		thenEqual(t, "Slept", time.Second, slept)
+		thenEqual(t, "LatencyError", nil, err)
+
+		_, _, err = whenGetting(client, server.URL)
+		thenEqual(t, "Reset", true, errors.Is(err, syscall.ECONNRESET))
+
+		status, body, _ = whenGetting(client, server.URL)
+		thenEqual(t, "Status", http.StatusBadGateway, status)
+		thenEqual(t, "Body", "<html><body><h1>Bad Gateway</h1></body></html>", body)
+
+		_, body, err = whenGetting(client, server.URL)
+		thenEqual(t, "TruncatedBody", `{"data":{`, body)
+		thenEqual(t, "TruncatedError", io.ErrUnexpectedEOF, err)
+
+		status, _, err = whenGetting(client, server.URL)
+		thenEqual(t, "ExhaustedStatus", http.StatusOK, status)
+		thenEqual(t, "ExhaustedError", nil, err)
+		thenEqual(t, "Calls", 5, transport.Calls())
+	})
+
+	t.Run("When rules are set then matching requests fail with the probability", func(t *testing.T) {
+		transport := &Transport{
+			Rules: []Rule{
+				{Fault: Status(http.StatusTeapot, ""), Probability: 1, Match: MatchPath(http.MethodPost, "/")},
+				{Fault: Status(http.StatusServiceUnavailable, ""), Probability: 0.5},
+			},
+			Rand: rand.New(rand.NewSource(1)),
+		}
+		client := &http.Client{Transport: transport}
+
+		failures := 0
+		for idx := 0; idx < 1000; idx++ {
+			status, _, err := whenGetting(client, server.URL)
+			thenEqual(t, "Error", nil, err)
+			if status == http.StatusServiceUnavailable {
+				failures++
+			}
+		}
+
+		thenEqual(t, "Failures", true, failures > 400 && failures < 600)
+	})
+
+	t.Run("When latency exceeds request timeout then request fails", func(t *testing.T) {
+		client := &http.Client{Transport: &Transport{Script: []Fault{Latency(time.Minute)}}, Timeout: 10 * time.Millisecond}
+
+		_, _, err := whenGetting(client, server.URL)
+		thenEqual(t, "Error", true, err != nil)
+	})
+}
+
+func whenGetting(client *http.Client, url string) (int, string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), err
+}
+
+func thenEqual(t *testing.T, name string, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s:\nExpected: %#v\n  Actual: %#v", name, expected, actual)
+	}
+}
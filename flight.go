@@ -0,0 +1,58 @@
+package form3
+
+import "sync"
+
+// flightGroup de-duplicates concurrent fetches of the same account: callers arriving while a fetch is in flight wait
+// for it and share its result instead of sending their own request.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	wg      sync.WaitGroup
+	account Account
+	err     error
+	dups    int
+}
+
+// do calls fetch unless a call with the same key is in flight, in which case it waits for that call and returns its
+// result.
+func (g *flightGroup) do(key string, fetch func() (Account, error)) (Account, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+
+	if call, ok := g.calls[key]; ok {
+		call.dups++
+		g.mu.Unlock()
+		call.wg.Wait()
+
+		return cloneAccount(call.account), call.err
+	}
+
+	call := &flightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.account, call.err = fetch()
+
+	g.mu.Lock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+	call.wg.Done()
+
+	return cloneAccount(call.account), call.err
+}
+
+// forget drops the call with the key, so later callers send their own request instead of waiting for a call which may
+// return a result older than a change made in the meantime. Callers already waiting still share its result.
+func (g *flightGroup) forget(key string) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+}
@@ -0,0 +1,163 @@
+package form3test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+const (
+	// AccountsPath is a collection of accounts used by name checks.
+	AccountsPath = "/v1/organisation/accounts"
+	// AccountIdentificationsPath is an endpoint performing Confirmation of Payee name checks.
+	AccountIdentificationsPath = "/v1/transaction/account-identifications"
+
+	closeMatchDistance = 2
+)
+
+type accountAttributes struct {
+	AccountMatchingOptOut       bool     `json:"account_matching_opt_out"`
+	AccountNumber               string   `json:"account_number"`
+	AlternativeBankAccountNames []string `json:"alternative_bank_account_names"`
+	BankAccountName             string   `json:"bank_account_name"`
+	BankID                      string   `json:"bank_id"`
+	BankIDCode                  string   `json:"bank_id_code"`
+}
+
+type identificationAttributes struct {
+	Name          string `json:"name"`
+	AccountNumber string `json:"account_number"`
+	BankID        string `json:"bank_id"`
+	BankIDCode    string `json:"bank_id_code"`
+}
+
+// serveAccountIdentification matches the name against accounts stored in the fake. Names equal after normalisation
+// are a full match, names within a small edit distance are a close match.
+func (s *Server) serveAccountIdentification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	identification := jsonapi.Resource{}
+	attributes := identificationAttributes{}
+	if err := jsonapi.Decode(r.Body, &jsonapi.Document{Data: &identification}); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := identification.DecodeAttributes(&attributes); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Attributes of the request are echoed as sent, only the match fields are set.
+	echoed := map[string]json.RawMessage{}
+	if err := identification.DecodeAttributes(&echoed); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	matchResult, actualName := s.matchName(attributes)
+	echoed["match_result"] = quote(matchResult)
+	delete(echoed, "actual_name")
+	if actualName != "" {
+		echoed["actual_name"] = quote(actualName)
+	}
+
+	raw, err := json.Marshal(echoed)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	identification.Attributes = raw
+	identification.CreatedOn = s.timestamp()
+	WriteDocument(w, http.StatusCreated, jsonapi.Document{Data: identification})
+}
+
+func (s *Server) matchName(identification identificationAttributes) (string, string) {
+	for _, resource := range s.Resources(AccountsPath) {
+		account := accountAttributes{}
+		if err := resource.DecodeAttributes(&account); err != nil {
+			continue
+		}
+
+		if account.AccountNumber != identification.AccountNumber || account.BankID != identification.BankID {
+			continue
+		}
+		if identification.BankIDCode != "" && account.BankIDCode != identification.BankIDCode {
+			continue
+		}
+
+		if account.AccountMatchingOptOut {
+			return "opted_out", ""
+		}
+
+		name := normaliseName(identification.Name)
+		for _, holder := range append([]string{account.BankAccountName}, account.AlternativeBankAccountNames...) {
+			if normaliseName(holder) == name {
+				return "full_match", ""
+			}
+		}
+
+		for _, holder := range append([]string{account.BankAccountName}, account.AlternativeBankAccountNames...) {
+			if distance(normaliseName(holder), name) <= closeMatchDistance {
+				return "close_match", holder
+			}
+		}
+
+		return "no_match", ""
+	}
+
+	return "no_match", ""
+}
+
+func quote(value string) json.RawMessage {
+	raw, _ := json.Marshal(value)
+	return raw
+}
+
+func normaliseName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			current[j] = minimum(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(rb)]
+}
+
+func minimum(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,75 @@
+package form3test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+// AuditEntriesPath is a base path of audit entries. Entries of a record are listed under
+// AuditEntriesPath/{record_type}/{record_id}.
+const AuditEntriesPath = "/v1/audit/entries"
+
+type auditAttributes struct {
+	Action      string          `json:"action"`
+	ActionTime  string          `json:"action_time"`
+	Description string          `json:"description"`
+	RecordType  string          `json:"record_type"`
+	RecordID    string          `json:"record_id"`
+	BeforeData  json.RawMessage `json:"before_data,omitempty"`
+	AfterData   json.RawMessage `json:"after_data,omitempty"`
+}
+
+// audit records an entry for a change of a typed resource. The caller must hold the lock.
+func (s *Server) audit(action string, before, after *jsonapi.Resource) {
+	record := after
+	if record == nil {
+		record = before
+	}
+	if record.Type == "" {
+		return
+	}
+
+	attributes := auditAttributes{
+		Action:      action,
+		ActionTime:  s.timestamp(),
+		Description: record.Type + " " + action,
+		RecordType:  record.Type,
+		RecordID:    record.ID,
+		BeforeData:  snapshot(before),
+		AfterData:   snapshot(after),
+	}
+
+	raw, err := json.Marshal(attributes)
+	if err != nil {
+		return
+	}
+
+	s.put(AuditEntriesPath+"/"+record.Type+"/"+record.ID, jsonapi.Resource{
+		ID:             randomUUID(),
+		OrganisationID: record.OrganisationID,
+		Type:           "audit_entries",
+		Attributes:     raw,
+	})
+}
+
+func snapshot(resource *jsonapi.Resource) json.RawMessage {
+	if resource == nil {
+		return nil
+	}
+
+	raw, _ := json.Marshal(resource)
+	return raw
+}
+
+func randomUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
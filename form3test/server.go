@@ -0,0 +1,516 @@
+// Package form3test provides an in-memory fake of the Form3 API for tests.
+//
+// The fake server is resource agnostic: any JSON:API resource POSTed to a path is stored under that path and served
+// back by GET, PATCH and DELETE with the same envelope, versioning, pagination and error messages as the real API.
+// Fetched resources carry ETag and Last-Modified headers and conditional GETs are answered with 304 Not Modified.
+// Endpoints which need more than storage are registered with Handle. Confirmation of Payee name checks are served out
+// of the box and match names against accounts stored in the fake.
+package form3test
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+const defaultPageSize = 100
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Server is an in-memory fake of the Form3 API.
+type Server struct {
+	*httptest.Server
+
+	// Now returns the time used for created_on and modified_on. Default: time.Now.
+	Now func() time.Time
+
+	mu          sync.Mutex
+	resources   map[string]jsonapi.Resource
+	collections map[string][]string
+	handlers    map[string]http.Handler
+}
+
+// NewServer starts a new fake server. It should be closed when the test finishes.
+func NewServer() *Server {
+	s := &Server{
+		Now:         time.Now,
+		resources:   map[string]jsonapi.Resource{},
+		collections: map[string][]string{},
+		handlers:    map[string]http.Handler{},
+	}
+	s.handlers[AccountIdentificationsPath] = http.HandlerFunc(s.serveAccountIdentification)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Handle registers a handler for an exact path. Registered handlers take precedence over the resource store.
+func (s *Server) Handle(path string, handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[path] = handler
+}
+
+// HandleFunc registers a handler function for an exact path.
+func (s *Server) HandleFunc(path string, handler func(http.ResponseWriter, *http.Request)) {
+	s.Handle(path, http.HandlerFunc(handler))
+}
+
+// Put stores a resource in a collection, replacing the resource with the same ID.
+func (s *Server) Put(collectionPath string, resource jsonapi.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(collectionPath, resource)
+}
+
+// Get returns a resource stored in a collection.
+func (s *Server) Get(collectionPath, id string) (jsonapi.Resource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resource, ok := s.resources[resourcePath(collectionPath, id)]
+	return resource, ok
+}
+
+// Resources returns all resources of a collection in creation order.
+func (s *Server) Resources(collectionPath string) []jsonapi.Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths := s.collections[strings.TrimSuffix(collectionPath, "/")]
+	resources := make([]jsonapi.Resource, 0, len(paths))
+	for _, path := range paths {
+		resources = append(resources, s.resources[path])
+	}
+
+	return resources
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+
+	s.mu.Lock()
+	handler, ok := s.handlers[path]
+	s.mu.Unlock()
+
+	if ok {
+		handler.ServeHTTP(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		s.create(w, r, path)
+	case http.MethodGet:
+		s.fetchOrList(w, r, path)
+	case http.MethodPatch:
+		s.update(w, r, path)
+	case http.MethodDelete:
+		s.delete(w, r, path)
+	default:
+		WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, collectionPath string) {
+	resource := jsonapi.Resource{}
+	if err := jsonapi.Decode(r.Body, &jsonapi.Document{Data: &resource}); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if resource.ID == "" {
+		WriteError(w, http.StatusBadRequest, "validation failure list:\nid in body is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.missingParent(collectionPath); ok {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+
+	if _, ok := s.resources[resourcePath(collectionPath, resource.ID)]; ok {
+		WriteError(w, http.StatusConflict, fmt.Sprintf("%s cannot be created as it violates a duplicate constraint", recordName(resource.Type)))
+		return
+	}
+
+	now := s.timestamp()
+	resource.Version = 0
+	resource.CreatedOn = now
+	resource.ModifiedOn = now
+	s.put(collectionPath, resource)
+	s.audit("created", nil, &resource)
+
+	WriteDocument(w, http.StatusCreated, jsonapi.Document{Data: resource})
+}
+
+func (s *Server) fetchOrList(w http.ResponseWriter, r *http.Request, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if resource, ok := s.resources[path]; ok {
+		etag, modified := validators(resource)
+		w.Header().Set("ETag", etag)
+		if !modified.IsZero() {
+			w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		}
+
+		if notModified(r, etag, modified) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		WriteDocument(w, http.StatusOK, jsonapi.Document{Data: resource})
+		return
+	}
+
+	if _, ok := s.collections[path]; ok {
+		s.list(w, r.URL, path)
+		return
+	}
+
+	if id := lastSegment(path); uuidRegex.MatchString(id) {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+
+	if id, ok := s.missingParent(path); ok {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+
+	s.list(w, r.URL, path)
+}
+
+func (s *Server) list(w http.ResponseWriter, reqURL *url.URL, collectionPath string) {
+	query := reqURL.Query()
+
+	filtered := make([]jsonapi.Resource, 0)
+	for _, path := range s.collections[collectionPath] {
+		if resource := s.resources[path]; matches(resource, query) {
+			filtered = append(filtered, resource)
+		}
+	}
+
+	page, err := strconv.Atoi(query.Get("page[number]"))
+	if err != nil || page < 0 {
+		page = 0
+	}
+	size, err := strconv.Atoi(query.Get("page[size]"))
+	if err != nil || size <= 0 {
+		size = defaultPageSize
+	}
+
+	from, to := page*size, (page+1)*size
+	if from > len(filtered) {
+		from = len(filtered)
+	}
+	if to > len(filtered) {
+		to = len(filtered)
+	}
+
+	links := &jsonapi.Links{
+		Self:  pageLink(reqURL, page, size),
+		First: pageLink(reqURL, 0, size),
+	}
+	if last := (len(filtered) - 1) / size; last >= 0 {
+		links.Last = pageLink(reqURL, last, size)
+	}
+	if to < len(filtered) {
+		links.Next = pageLink(reqURL, page+1, size)
+	}
+	if page > 0 {
+		links.Previous = pageLink(reqURL, page-1, size)
+	}
+
+	WriteDocument(w, http.StatusOK, jsonapi.Document{Data: filtered[from:to], Links: links})
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, path string) {
+	patch := jsonapi.Resource{}
+	if err := jsonapi.Decode(r.Body, &jsonapi.Document{Data: &patch}); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resource, ok := s.resources[path]
+	if !ok {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", lastSegment(path)))
+		return
+	}
+
+	if patch.Version != resource.Version {
+		WriteError(w, http.StatusConflict, "invalid version")
+		return
+	}
+	before := resource
+
+	attributes, err := mergeAttributes(resource.Attributes, patch.Attributes)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resource.Attributes = attributes
+	if patch.Relationships != nil {
+		resource.Relationships = patch.Relationships
+	}
+	resource.Version++
+	resource.ModifiedOn = s.timestamp()
+	s.resources[path] = resource
+	s.audit("updated", &before, &resource)
+
+	WriteDocument(w, http.StatusOK, jsonapi.Document{Data: resource})
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resource, ok := s.resources[path]
+	if !ok {
+		WriteError(w, http.StatusNotFound, "")
+		return
+	}
+
+	if version, err := strconv.Atoi(r.URL.Query().Get("version")); err != nil || version != resource.Version {
+		WriteError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	delete(s.resources, path)
+	s.audit("deleted", &resource, nil)
+
+	collectionPath := parentPath(path)
+	paths := s.collections[collectionPath]
+	for idx, p := range paths {
+		if p == path {
+			s.collections[collectionPath] = append(paths[:idx:idx], paths[idx+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// missingParent returns ID of the first resource in the path which does not exist, e.g. a payment of a submissions
+// collection.
+func (s *Server) missingParent(path string) (string, bool) {
+	segments := strings.Split(path, "/")
+	for idx, segment := range segments {
+		if !uuidRegex.MatchString(segment) {
+			continue
+		}
+
+		if _, ok := s.resources[strings.Join(segments[:idx+1], "/")]; !ok {
+			return segment, true
+		}
+	}
+
+	return "", false
+}
+
+func (s *Server) put(collectionPath string, resource jsonapi.Resource) {
+	collectionPath = strings.TrimSuffix(collectionPath, "/")
+	path := resourcePath(collectionPath, resource.ID)
+
+	if _, ok := s.resources[path]; !ok {
+		s.collections[collectionPath] = append(s.collections[collectionPath], path)
+	}
+	s.resources[path] = resource
+}
+
+func (s *Server) timestamp() string {
+	return s.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// WriteDocument writes a JSON:API document with the given status code.
+func WriteDocument(w http.ResponseWriter, statusCode int, doc jsonapi.Document) {
+	w.Header().Set("Content-Type", jsonapi.MediaType)
+	w.WriteHeader(statusCode)
+	_ = jsonapi.Encode(w, doc)
+}
+
+// WriteError writes an error body in the format used by the Form3 API.
+func WriteError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error_message": message})
+}
+
+// matches checks filter[field] query parameters against the resource. Values are compared with the top level member
+// or the attribute of the same name, comma separated values match any of them. Fields with _from and _to suffixes
+// filter ranges of an attribute, e.g. filter[value_date_from].
+func matches(resource jsonapi.Resource, query url.Values) bool {
+	var attributes map[string]interface{}
+	_ = json.Unmarshal(resource.Attributes, &attributes)
+
+	for key, values := range query {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+
+		field := key[len("filter[") : len(key)-1]
+
+		switch field {
+		case "id":
+			if !contains(strings.Split(values[0], ","), resource.ID) {
+				return false
+			}
+			continue
+		case "type":
+			if !contains(strings.Split(values[0], ","), resource.Type) {
+				return false
+			}
+			continue
+		case "organisation_id":
+			if !contains(strings.Split(values[0], ","), resource.OrganisationID) {
+				return false
+			}
+			continue
+		}
+
+		if value, ok := attributes[field]; ok {
+			if !contains(strings.Split(values[0], ","), fmt.Sprint(value)) {
+				return false
+			}
+			continue
+		}
+
+		if value, ok := attributes[strings.TrimSuffix(field, "_from")]; ok && strings.HasSuffix(field, "_from") {
+			if fmt.Sprint(value) < values[0] {
+				return false
+			}
+			continue
+		}
+
+		if value, ok := attributes[strings.TrimSuffix(field, "_to")]; ok && strings.HasSuffix(field, "_to") {
+			if fmt.Sprint(value) > values[0] {
+				return false
+			}
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// validators returns the entity tag of the resource, a hash of its representation, and its modification time.
+func validators(resource jsonapi.Resource) (string, time.Time) {
+	raw, _ := json.Marshal(resource)
+	etag := fmt.Sprintf(`"%x"`, sha1.Sum(raw))
+
+	modified, err := time.Parse(time.RFC3339Nano, resource.ModifiedOn)
+	if err != nil {
+		return etag, time.Time{}
+	}
+
+	return etag, modified.UTC()
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when there is no If-None-Match, as described by RFC 7232.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.IsZero() {
+		return false
+	}
+
+	return !modified.Truncate(time.Second).After(since)
+}
+
+func mergeAttributes(current, patch json.RawMessage) (json.RawMessage, error) {
+	if len(patch) == 0 {
+		return current, nil
+	}
+
+	merged := map[string]interface{}{}
+	if len(current) > 0 {
+		if err := json.Unmarshal(current, &merged); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := map[string]interface{}{}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, err
+	}
+
+	for key, value := range changes {
+		merged[key] = value
+	}
+
+	return json.Marshal(merged)
+}
+
+func pageLink(reqURL *url.URL, page, size int) string {
+	query := url.Values{}
+	for key, values := range reqURL.Query() {
+		query[key] = values
+	}
+	query.Set("page[number]", strconv.Itoa(page))
+	query.Set("page[size]", strconv.Itoa(size))
+
+	return (&url.URL{Path: reqURL.Path, RawQuery: query.Encode()}).String()
+}
+
+func recordName(typ string) string {
+	name := strings.TrimSuffix(typ, "s")
+	if name == "" {
+		return "Record"
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func resourcePath(collectionPath, id string) string {
+	return strings.TrimSuffix(collectionPath, "/") + "/" + id
+}
+
+func parentPath(path string) string {
+	return path[:strings.LastIndex(path, "/")]
+}
+
+func lastSegment(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,181 @@
+package form3test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+const (
+	testCollection = "/v1/things"
+	testID         = "7b8d1a6e-0e0b-4a4c-9d3b-1f2e3d4c5b6a"
+)
+
+func TestServer(t *testing.T) {
+	t.Run("When creating duplicate resource then conflict is returned", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		body := `{"data":{"id":"` + testID + `","type":"things","attributes":{"name":"first"}}}`
+		thenStatus(t, whenRequesting(t, server, http.MethodPost, testCollection, body), http.StatusCreated)
+
+		resp := whenRequesting(t, server, http.MethodPost, testCollection, body)
+		thenStatus(t, resp, http.StatusConflict)
+		thenErrorMessage(t, resp, "Thing cannot be created as it violates a duplicate constraint")
+	})
+
+	t.Run("When fetching not existing resource then not found is returned", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		resp := whenRequesting(t, server, http.MethodGet, testCollection+"/"+testID, "")
+		thenStatus(t, resp, http.StatusNotFound)
+		thenErrorMessage(t, resp, "record "+testID+" does not exist")
+	})
+
+	t.Run("When listing with filter then only matching resources are returned", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		server.Put(testCollection, jsonapi.Resource{ID: "1", Attributes: json.RawMessage(`{"country":"GB"}`)})
+		server.Put(testCollection, jsonapi.Resource{ID: "2", Attributes: json.RawMessage(`{"country":"FR"}`)})
+		server.Put(testCollection, jsonapi.Resource{ID: "3", Attributes: json.RawMessage(`{"country":"GB"}`)})
+
+		resp := whenRequesting(t, server, http.MethodGet, testCollection+"?filter[country]=GB&page[size]=1", "")
+		thenStatus(t, resp, http.StatusOK)
+
+		var resources []jsonapi.Resource
+		doc := jsonapi.Document{Data: &resources}
+		if err := jsonapi.Decode(resp.Body, &doc); err != nil {
+			t.Fatalf("error while decoding response, %s", err.Error())
+		}
+
+		thenEqual(t, "Length", 1, len(resources))
+		thenEqual(t, "ID", "1", resources[0].ID)
+		thenEqual(t, "HasNext", true, doc.Links.HasNext())
+	})
+
+	t.Run("When listing negative page then first page is returned", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		server.Put(testCollection, jsonapi.Resource{ID: "1"})
+		server.Put(testCollection, jsonapi.Resource{ID: "2"})
+
+		resp := whenRequesting(t, server, http.MethodGet, testCollection+"?page[number]=-1&page[size]=1", "")
+		thenStatus(t, resp, http.StatusOK)
+
+		var resources []jsonapi.Resource
+		doc := jsonapi.Document{Data: &resources}
+		if err := jsonapi.Decode(resp.Body, &doc); err != nil {
+			t.Fatalf("error while decoding response, %s", err.Error())
+		}
+
+		thenEqual(t, "Length", 1, len(resources))
+		thenEqual(t, "ID", "1", resources[0].ID)
+		thenEqual(t, "HasPrevious", false, doc.Links.Previous != "")
+	})
+
+	t.Run("When deleting with wrong version then conflict is returned", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		server.Put(testCollection, jsonapi.Resource{ID: testID, Version: 1})
+
+		thenStatus(t, whenRequesting(t, server, http.MethodDelete, testCollection+"/"+testID+"?version=0", ""), http.StatusConflict)
+		thenStatus(t, whenRequesting(t, server, http.MethodDelete, testCollection+"/"+testID+"?version=1", ""), http.StatusNoContent)
+		thenEqual(t, "Resources", 0, len(server.Resources(testCollection)))
+	})
+
+	t.Run("When fetching with matching validators then not modified is returned", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		server.Put(testCollection, jsonapi.Resource{ID: testID, ModifiedOn: "2020-01-01T10:00:00.5Z"})
+
+		resp := whenRequesting(t, server, http.MethodGet, testCollection+"/"+testID, "")
+		thenStatus(t, resp, http.StatusOK)
+		thenEqual(t, "LastModified", "Wed, 01 Jan 2020 10:00:00 GMT", resp.Header.Get("Last-Modified"))
+
+		etag := resp.Header.Get("ETag")
+		thenStatus(t, whenFetchingConditionally(t, server, testID, "If-None-Match", etag), http.StatusNotModified)
+		thenStatus(t, whenFetchingConditionally(t, server, testID, "If-None-Match", `"stale"`), http.StatusOK)
+		thenStatus(t, whenFetchingConditionally(t, server, testID, "If-Modified-Since", "Wed, 01 Jan 2020 10:00:00 GMT"), http.StatusNotModified)
+		thenStatus(t, whenFetchingConditionally(t, server, testID, "If-Modified-Since", "Wed, 01 Jan 2020 09:59:59 GMT"), http.StatusOK)
+
+		server.Put(testCollection, jsonapi.Resource{ID: testID, Version: 1, ModifiedOn: "2020-01-01T10:00:00.5Z"})
+		thenStatus(t, whenFetchingConditionally(t, server, testID, "If-None-Match", etag), http.StatusOK)
+	})
+
+	t.Run("When handler is registered then it takes precedence over the store", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		server.HandleFunc(testCollection, func(w http.ResponseWriter, r *http.Request) {
+			WriteError(w, http.StatusTeapot, "custom")
+		})
+
+		thenStatus(t, whenRequesting(t, server, http.MethodGet, testCollection, ""), http.StatusTeapot)
+	})
+}
+
+func whenRequesting(t *testing.T, server *Server, method, path, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("error while creating request, %s", err.Error())
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("error while calling server, %s", err.Error())
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+func whenFetchingConditionally(t *testing.T, server *Server, id, header, value string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+testCollection+"/"+id, nil)
+	if err != nil {
+		t.Fatalf("error while creating request, %s", err.Error())
+	}
+	req.Header.Set(header, value)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("error while calling server, %s", err.Error())
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+func thenStatus(t *testing.T, resp *http.Response, expected int) {
+	t.Helper()
+	thenEqual(t, "StatusCode", expected, resp.StatusCode)
+}
+
+func thenErrorMessage(t *testing.T, resp *http.Response, expected string) {
+	t.Helper()
+
+	body := map[string]string{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("error while decoding error body, %s", err.Error())
+	}
+	thenEqual(t, "ErrorMessage", expected, body["error_message"])
+}
+
+func thenEqual(t *testing.T, name string, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s:\nExpected: %#v\n  Actual: %#v", name, expected, actual)
+	}
+}
@@ -1,3 +1,8 @@
 module github.com/ptrsd/form3
 
-go 1.14
\ No newline at end of file
+go 1.14
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	gopkg.in/yaml.v2 v2.4.0
+)
@@ -0,0 +1,134 @@
+// Package jsonapi implements the JSON:API envelope used by the Form3 API.
+//
+// Documents are generic over the resource type. Data holds whatever value the caller provides, so the same Document
+// encodes a single resource, decodes a collection into a slice, or decodes into a raw Resource when the type is not
+// known up front.
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// MediaType is the media type of JSON:API documents.
+const MediaType = "application/vnd.api+json"
+
+// Document is a top level JSON:API document.
+type Document struct {
+	// Data is a primary data of the document. When decoding it must be a pointer to a value able to hold the resource,
+	// e.g. *Account for a single resource or *[]Account for a collection.
+	Data     interface{} `json:"data,omitempty"`
+	Included []Resource  `json:"included,omitempty"`
+	Links    *Links      `json:"links,omitempty"`
+	Meta     Meta        `json:"meta,omitempty"`
+	Errors   []Error     `json:"errors,omitempty"`
+}
+
+// Resource is a resource object with attributes kept in their raw form. It is used for included resources and for
+// documents which primary data type is not known up front.
+type Resource struct {
+	ID             string          `json:"id,omitempty"`
+	Type           string          `json:"type,omitempty"`
+	OrganisationID string          `json:"organisation_id,omitempty"`
+	Version        int             `json:"version,omitempty"`
+	CreatedOn      string          `json:"created_on,omitempty"`
+	ModifiedOn     string          `json:"modified_on,omitempty"`
+	Attributes     json.RawMessage `json:"attributes,omitempty"`
+	Relationships  Relationships   `json:"relationships,omitempty"`
+	Links          *Links          `json:"links,omitempty"`
+	Meta           Meta            `json:"meta,omitempty"`
+}
+
+// Links holds links of a document, a resource or a relationship.
+type Links struct {
+	Self     string `json:"self,omitempty"`
+	Related  string `json:"related,omitempty"`
+	First    string `json:"first,omitempty"`
+	Last     string `json:"last,omitempty"`
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"prev,omitempty"`
+}
+
+// Meta holds non-standard meta-information.
+type Meta map[string]interface{}
+
+// Error is an error object returned by the server.
+type Error struct {
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Encode writes the document to w.
+func Encode(w io.Writer, doc Document) error {
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// Decode reads a document from r. Primary data is decoded into doc.Data, so it has to be set to a pointer before
+// calling Decode.
+func Decode(r io.Reader, doc *Document) error {
+	return json.NewDecoder(r).Decode(doc)
+}
+
+// HasNext returns true if links point to the next page of a collection. It is safe to call on nil links.
+func (l *Links) HasNext() bool {
+	return l != nil && l.Next != ""
+}
+
+// DecodeAttributes decodes raw attributes of the resource into v.
+func (r Resource) DecodeAttributes(v interface{}) error {
+	if len(r.Attributes) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(r.Attributes, v)
+}
+
+// Decode decodes the whole resource into v, e.g. an included account into *Account.
+func (r Resource) Decode(v interface{}) error {
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, v)
+}
+
+// FindIncluded looks up an included resource by type and ID.
+func (d *Document) FindIncluded(typ, id string) (Resource, bool) {
+	for _, resource := range d.Included {
+		if resource.Type == typ && resource.ID == id {
+			return resource, true
+		}
+	}
+
+	return Resource{}, false
+}
+
+// DecodeIncluded decodes an included resource identified by type and ID into v. It returns an error if the document
+// does not include the resource.
+func (d *Document) DecodeIncluded(typ, id string, v interface{}) error {
+	resource, ok := d.FindIncluded(typ, id)
+	if !ok {
+		return fmt.Errorf("jsonapi: resource %s/%s not included", typ, id)
+	}
+
+	return resource.Decode(v)
+}
+
+// Error returns the most descriptive message available for the error object.
+func (e Error) Error() string {
+	switch {
+	case e.Detail != "":
+		return e.Detail
+	case e.Title != "":
+		return e.Title
+	case e.Code != "":
+		return e.Code
+	default:
+		return e.Status
+	}
+}
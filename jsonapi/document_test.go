@@ -0,0 +1,127 @@
+package jsonapi
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testAttributes struct {
+	Name string `json:"name,omitempty"`
+}
+
+type testResource struct {
+	ID            string         `json:"id,omitempty"`
+	Type          string         `json:"type,omitempty"`
+	Attributes    testAttributes `json:"attributes"`
+	Relationships Relationships  `json:"relationships,omitempty"`
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("When decoding single resource then data is decoded into provided value", func(t *testing.T) {
+		body := `{"data":{"id":"1","type":"things","attributes":{"name":"first"}},"links":{"self":"/things/1"}}`
+
+		resource := testResource{}
+		doc := Document{Data: &resource}
+		if err := Decode(strings.NewReader(body), &doc); err != nil {
+			t.Fatalf("error while decoding document, %s", err.Error())
+		}
+
+		thenEqual(t, "Resource", testResource{ID: "1", Type: "things", Attributes: testAttributes{Name: "first"}}, resource)
+		thenEqual(t, "Links.Self", "/things/1", doc.Links.Self)
+		thenEqual(t, "HasNext", false, doc.Links.HasNext())
+	})
+
+	t.Run("When decoding collection then data is decoded into provided slice", func(t *testing.T) {
+		body := `{"data":[{"id":"1"},{"id":"2"}],"links":{"next":"/things?page[number]=1"},"meta":{"count":2}}`
+
+		var resources []testResource
+		doc := Document{Data: &resources}
+		if err := Decode(strings.NewReader(body), &doc); err != nil {
+			t.Fatalf("error while decoding document, %s", err.Error())
+		}
+
+		thenEqual(t, "Length", 2, len(resources))
+		thenEqual(t, "HasNext", true, doc.Links.HasNext())
+		thenEqual(t, "Meta.count", 2.0, doc.Meta["count"])
+	})
+
+	t.Run("When decoding relationships then single object and array are accepted", func(t *testing.T) {
+		body := `{"data":{"id":"1","relationships":{"one":{"data":{"type":"accounts","id":"a"}},` +
+			`"many":{"data":[{"type":"accounts","id":"b"},{"type":"accounts","id":"c"}]},"none":{"data":null}}}}`
+
+		resource := testResource{}
+		if err := Decode(strings.NewReader(body), &Document{Data: &resource}); err != nil {
+			t.Fatalf("error while decoding document, %s", err.Error())
+		}
+
+		thenEqual(t, "One", "a", resource.Relationships["one"].First())
+		thenEqual(t, "Many", []string{"b", "c"}, resource.Relationships["many"].IDs())
+		thenEqual(t, "None", "", resource.Relationships["none"].First())
+	})
+
+	t.Run("When document includes resources then they are decoded by type and id", func(t *testing.T) {
+		body := `{"data":{"id":"1"},"included":[{"id":"a","type":"accounts","version":2,"attributes":{"name":"included"}}]}`
+
+		doc := Document{Data: &testResource{}}
+		if err := Decode(strings.NewReader(body), &doc); err != nil {
+			t.Fatalf("error while decoding document, %s", err.Error())
+		}
+
+		included := testResource{}
+		if err := doc.DecodeIncluded("accounts", "a", &included); err != nil {
+			t.Fatalf("error while decoding included resource, %s", err.Error())
+		}
+		thenEqual(t, "Included", testResource{ID: "a", Type: "accounts", Attributes: testAttributes{Name: "included"}}, included)
+
+		if err := doc.DecodeIncluded("accounts", "missing", &included); err == nil {
+			t.Errorf("decoding not included resource should return error")
+		}
+	})
+
+	t.Run("When document has errors then they are decoded", func(t *testing.T) {
+		body := `{"errors":[{"status":"400","title":"Bad Request","detail":"name is required"},{"code":"E1"}]}`
+
+		doc := Document{}
+		if err := Decode(strings.NewReader(body), &doc); err != nil {
+			t.Fatalf("error while decoding document, %s", err.Error())
+		}
+
+		thenEqual(t, "Errors.0", "name is required", doc.Errors[0].Error())
+		thenEqual(t, "Errors.1", "E1", doc.Errors[1].Error())
+	})
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("When encoding resource then empty members are omitted", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		resource := testResource{
+			ID:            "1",
+			Relationships: Relationships{"account": NewRelationship("accounts", "a")},
+		}
+
+		if err := Encode(&buf, Document{Data: resource}); err != nil {
+			t.Fatalf("error while encoding document, %s", err.Error())
+		}
+
+		expected := `{"data":{"id":"1","attributes":{},"relationships":{"account":{"data":[{"type":"accounts","id":"a"}]}}}}`
+		thenEqual(t, "Document", expected, strings.TrimSpace(buf.String()))
+	})
+
+	t.Run("When encoding relationship without data then empty array is encoded", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		if err := Encode(&buf, Document{Data: Relationship{}}); err != nil {
+			t.Fatalf("error while encoding document, %s", err.Error())
+		}
+
+		thenEqual(t, "Document", `{"data":{"data":[]}}`, strings.TrimSpace(buf.String()))
+	})
+}
+
+func thenEqual(t *testing.T, name string, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s:\nExpected: %#v\n  Actual: %#v", name, expected, actual)
+	}
+}
@@ -0,0 +1,91 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Relationships maps relationship names to relationships of a resource.
+type Relationships map[string]Relationship
+
+// Relationship describes a link from one resource to other resources.
+type Relationship struct {
+	Data  RelationshipData `json:"data"`
+	Links *Links           `json:"links,omitempty"`
+	Meta  Meta             `json:"meta,omitempty"`
+}
+
+// ResourceIdentifier identifies a resource by its type and ID.
+type ResourceIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// RelationshipData holds resource identifiers of a relationship. The Form3 API encodes both to-one and to-many
+// relationships as arrays, so data is always encoded as an array, but a single object is accepted when decoding.
+type RelationshipData []ResourceIdentifier
+
+// NewRelationship creates a relationship pointing at the given resources of one type.
+func NewRelationship(typ string, ids ...string) Relationship {
+	data := make(RelationshipData, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, ResourceIdentifier{Type: typ, ID: id})
+	}
+
+	return Relationship{Data: data}
+}
+
+// IDs returns IDs of related resources.
+func (r Relationship) IDs() []string {
+	ids := make([]string, 0, len(r.Data))
+	for _, identifier := range r.Data {
+		ids = append(ids, identifier.ID)
+	}
+
+	return ids
+}
+
+// First returns ID of the first related resource or an empty string if there are none.
+func (r Relationship) First() string {
+	if len(r.Data) == 0 {
+		return ""
+	}
+
+	return r.Data[0].ID
+}
+
+// MarshalJSON encodes relationship data as an array, nil data is encoded as an empty array.
+func (d RelationshipData) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal([]ResourceIdentifier(d))
+}
+
+// UnmarshalJSON decodes relationship data from an array, a single resource identifier or null.
+func (d *RelationshipData) UnmarshalJSON(raw []byte) error {
+	raw = bytes.TrimSpace(raw)
+
+	switch {
+	case bytes.Equal(raw, []byte("null")):
+		*d = nil
+		return nil
+	case len(raw) > 0 && raw[0] == '{':
+		identifier := ResourceIdentifier{}
+		if err := json.Unmarshal(raw, &identifier); err != nil {
+			return err
+		}
+
+		*d = RelationshipData{identifier}
+		return nil
+	default:
+		var identifiers []ResourceIdentifier
+		if err := json.Unmarshal(raw, &identifiers); err != nil {
+			return err
+		}
+
+		*d = identifiers
+		return nil
+	}
+}
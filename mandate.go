@@ -0,0 +1,145 @@
+package form3
+
+import "github.com/ptrsd/form3/jsonapi"
+
+const (
+	transactionMandatesBasePath = "/v1/transaction/mandates"
+	mandateType                 = "mandates"
+)
+
+// MandateStatus describes a state of a direct debit mandate.
+type MandateStatus string
+
+const (
+	MandateStatusPending   MandateStatus = "pending"
+	MandateStatusActive    MandateStatus = "active"
+	MandateStatusCancelled MandateStatus = "cancelled"
+)
+
+// Mandate describes a direct debit mandate allowing a service user to collect payments from an account.
+type Mandate struct {
+	// ID is a mandatory, UUID version 4 field. It identifies mandate within a system.
+	ID string `json:"id,omitempty"`
+	// OrganisationID is a mandatory UUID version 4 field. It identifies organisation by which the mandate has been
+	// created.
+	OrganisationID string               `json:"organisation_id,omitempty"`
+	CreatedOn      string               `json:"created_on,omitempty"`
+	ModifiedOn     string               `json:"modified_on,omitempty"`
+	Type           string               `json:"type,omitempty"`
+	Version        int                  `json:"version,omitempty"`
+	Attributes     MandateAttributes    `json:"attributes,omitempty"`
+	Relationships  MandateRelationships `json:"relationships,omitempty"`
+}
+
+// MandateAttributes describes attributes of a mandate.
+type MandateAttributes struct {
+	// Scheme is a payment scheme used for collections, e.g. PaymentSchemeBacs.
+	Scheme PaymentScheme `json:"payment_scheme,omitempty"`
+	// Reference is a mandatory reference of the mandate agreed with the payer.
+	Reference          string        `json:"reference,omitempty"`
+	ServiceUserNumber  string        `json:"service_user_number,omitempty"`
+	Status             MandateStatus `json:"status,omitempty"`
+	SignatureDate      string        `json:"signature_date,omitempty"`
+	CancellationReason string        `json:"cancellation_reason,omitempty"`
+	DebtorParty        *PaymentParty `json:"debtor_party,omitempty"`
+	BeneficiaryParty   *PaymentParty `json:"beneficiary_party,omitempty"`
+}
+
+// MandateRelationships links a mandate to the collecting account.
+type MandateRelationships struct {
+	Account jsonapi.Relationship `json:"account"`
+}
+
+// MandateRequest describes a mandate to be created or amended.
+type MandateRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies mandate within a system.
+	ID             string `json:"id,omitempty"`
+	OrganisationID string `json:"organisation_id"`
+	Type           string `json:"type,omitempty"`
+	// Version is mandatory for amendments and must match the current version of the mandate.
+	Version int `json:"version"`
+	// AccountID is a mandatory ID of the Account collecting payments under the mandate. It is not changed by
+	// amendments.
+	AccountID  string            `json:"-"`
+	Attributes MandateAttributes `json:"attributes"`
+}
+
+type mandateCreate struct {
+	MandateRequest
+	Relationships MandateRelationships `json:"relationships"`
+}
+
+// AccountID returns ID of the Account collecting payments under the mandate.
+func (m Mandate) AccountID() string {
+	return m.Relationships.Account.First()
+}
+
+type MandateService struct {
+	client *Client
+}
+
+// Create a new mandate for the account given by AccountID. Returns Mandate or an error for network problem, and for
+// non-2xx server statuses.
+func (m *MandateService) Create(createReq MandateRequest) (Mandate, error) {
+	if createReq.Type == "" {
+		createReq.Type = mandateType
+	}
+	if createReq.OrganisationID == "" {
+		createReq.OrganisationID = m.client.OrganisationID
+	}
+
+	create := mandateCreate{
+		MandateRequest: createReq,
+		Relationships:  MandateRelationships{Account: jsonapi.NewRelationship(typ, createReq.AccountID)},
+	}
+
+	mandate := Mandate{}
+	err := m.client.createResource(transactionMandatesBasePath, create, &mandate)
+
+	return mandate, err
+}
+
+// Fetch a Mandate based on ID. Returns a mandate or an error for network problem, and for non-2xx server statuses.
+func (m *MandateService) Fetch(id string) (Mandate, error) {
+	mandate := Mandate{}
+	err := m.client.fetchResource(resourcePath(transactionMandatesBasePath, id), &mandate)
+
+	return mandate, err
+}
+
+// List mandates of Client.OrganisationID, or all mandates if it is empty. Accepts pagination options as an argument.
+// Returns list of mandates, true if there are more pages with mandates or an error for network problem, and for non-2xx
+// server statuses.
+func (m *MandateService) List(options ListOptions) ([]Mandate, bool, error) {
+	var mandates []Mandate
+	query := pagingQuery(options)
+	setFilter(query, "organisation_id", m.client.OrganisationID)
+
+	hasNext, err := m.client.listResources(transactionMandatesBasePath, query, &mandates)
+
+	return mandates, hasNext, err
+}
+
+// Amend attributes of a mandate. Only non-empty attributes are changed. The request version must match the current
+// version of the mandate. Returns the amended mandate or an error for network problem, and for non-2xx server
+// statuses.
+func (m *MandateService) Amend(amendReq MandateRequest) (Mandate, error) {
+	if amendReq.Type == "" {
+		amendReq.Type = mandateType
+	}
+
+	mandate := Mandate{}
+	err := m.client.updateResource(resourcePath(transactionMandatesBasePath, amendReq.ID), amendReq, &mandate)
+
+	return mandate, err
+}
+
+// Cancel a mandate in the given version, so no further payments are collected. Returns the cancelled mandate or an
+// error for network problem, and for non-2xx server statuses.
+func (m *MandateService) Cancel(id string, version int, reason string) (Mandate, error) {
+	return m.Amend(MandateRequest{
+		ID:         id,
+		Version:    version,
+		Attributes: MandateAttributes{Status: MandateStatusCancelled, CancellationReason: reason},
+	})
+}
@@ -0,0 +1,122 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestMandateService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	account, err := givenMinimalAccount(client)
+	if err != nil {
+		t.Fatalf("error while generating minimal account, %s", err.Error())
+	}
+
+	t.Run("When creating mandate then it is linked to the account", func(t *testing.T) {
+		mandate, err := givenMandate(client, account)
+		if err != nil {
+			t.Fatalf("create mandate returned with error %v", err.Error())
+		}
+
+		fetched, err := client.MandateService.Fetch(mandate.ID)
+		if err != nil {
+			t.Fatalf("fetch mandate returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: fetched.AccountID(), expected: account.ID, name: "AccountID"},
+			{actual: fetched.Relationships.Account.Data[0].Type, expected: typ, name: "AccountType"},
+			{actual: fetched.Attributes.Status, expected: MandateStatusPending, name: "Status"},
+		})
+	})
+
+	t.Run("When amending mandate then changed attributes are updated", func(t *testing.T) {
+		mandate, err := givenMandate(client, account)
+		if err != nil {
+			t.Fatalf("create mandate returned with error %v", err.Error())
+		}
+
+		amended, err := client.MandateService.Amend(MandateRequest{
+			ID:         mandate.ID,
+			Version:    mandate.Version,
+			Attributes: MandateAttributes{Reference: "NEW-REF"},
+		})
+		if err != nil {
+			t.Fatalf("amend mandate returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: amended.Attributes.Reference, expected: "NEW-REF", name: "Reference"},
+			{actual: amended.Attributes.ServiceUserNumber, expected: mandate.Attributes.ServiceUserNumber, name: "ServiceUserNumber"},
+			{actual: amended.Version, expected: 1, name: "Version"},
+			{actual: amended.AccountID(), expected: account.ID, name: "AccountID"},
+		})
+	})
+
+	t.Run("When cancelling mandate then it is cancelled with reason", func(t *testing.T) {
+		mandate, err := givenMandate(client, account)
+		if err != nil {
+			t.Fatalf("create mandate returned with error %v", err.Error())
+		}
+
+		cancelled, err := client.MandateService.Cancel(mandate.ID, mandate.Version, "payer request")
+		if err != nil {
+			t.Fatalf("cancel mandate returned with error %v", err.Error())
+		}
+
+		mandates, _, err := client.MandateService.List(ListOptions{})
+		if err != nil {
+			t.Fatalf("list mandates returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: cancelled.Attributes.Status, expected: MandateStatusCancelled, name: "Status"},
+			{actual: cancelled.Attributes.CancellationReason, expected: "payer request", name: "CancellationReason"},
+			{actual: len(mandates), expected: 3, name: "Mandates"},
+		})
+	})
+	t.Run("When client is scoped to organisation then only its mandates are listed", func(t *testing.T) {
+		otherAccount, err := givenMinimalAccount(client)
+		if err != nil {
+			t.Fatalf("error while generating minimal account, %s", err.Error())
+		}
+
+		mandate, err := givenMandate(client, otherAccount)
+		if err != nil {
+			t.Fatalf("create mandate returned with error %v", err.Error())
+		}
+
+		mandates, _, err := client.ForOrganisation(otherAccount.OrganisationID).MandateService.List(ListOptions{})
+		if err != nil {
+			t.Fatalf("list mandates returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: mandates, expected: []Mandate{mandate}, name: "Mandates"},
+		})
+	})
+}
+
+func givenMandate(client *Client, account Account) (Mandate, error) {
+	id, err := generateRandomUUID()
+	if err != nil {
+		return Mandate{}, err
+	}
+
+	return client.MandateService.Create(MandateRequest{
+		ID:             id,
+		OrganisationID: account.OrganisationID,
+		AccountID:      account.ID,
+		Attributes: MandateAttributes{
+			Scheme:            PaymentSchemeBacs,
+			Reference:         "REF-1",
+			ServiceUserNumber: "112238",
+			Status:            MandateStatusPending,
+		},
+	})
+}
@@ -0,0 +1,124 @@
+// Package manifest manages accounts declared in YAML or JSON manifests, computing and applying plans which bring the
+// API to the declared state.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ptrsd/form3"
+	"gopkg.in/yaml.v2"
+)
+
+// Manifest declares the desired state of accounts.
+//
+//	organisation_id: eb0bd6f5-c3f5-44b2-b677-acd23cdde73c
+//	accounts:
+//	  - id: ad27e265-9605-4b4b-a0e5-3003ea9cc4dc
+//	    attributes:
+//	      country: GB
+//	      bank_id: "400300"
+type Manifest struct {
+	// OrganisationID owns the declared accounts. It is used for accounts which do not have an organisation ID, and
+	// accounts of this organisation which are not declared are deleted. Without it, plans never delete accounts.
+	OrganisationID string `json:"organisation_id,omitempty"`
+	// Accounts are declared accounts. Versions are ignored, plans use current versions of accounts.
+	Accounts []form3.AccountRequest `json:"accounts"`
+}
+
+// Read reads a manifest. YAML is a superset of JSON, so both formats are accepted. Field names are the JSON names of
+// account fields.
+func Read(r io.Reader) (Manifest, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal(raw, &document); err != nil {
+		return Manifest{}, fmt.Errorf("error while parsing manifest, %w", err)
+	}
+
+	// yaml decodes mappings with interface{} keys, which encoding/json does not support.
+	document, err = stringKeys(document)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manifest := Manifest{}
+	if err := json.Unmarshal(encoded, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("error while parsing manifest, %w", err)
+	}
+
+	return manifest, manifest.validate()
+}
+
+// ReadFile reads a manifest from a file.
+func ReadFile(path string) (Manifest, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return Read(bytes.NewReader(raw))
+}
+
+func (m *Manifest) validate() error {
+	declared := make(map[string]bool, len(m.Accounts))
+	for idx := range m.Accounts {
+		account := &m.Accounts[idx]
+		if account.ID == "" {
+			return fmt.Errorf("account %d has no id", idx)
+		}
+		if declared[account.ID] {
+			return fmt.Errorf("account %s is declared more than once", account.ID)
+		}
+		declared[account.ID] = true
+
+		if account.OrganisationID == "" {
+			account.OrganisationID = m.OrganisationID
+		}
+		if account.OrganisationID == "" {
+			return fmt.Errorf("account %s has no organisation_id", account.ID)
+		}
+	}
+
+	return nil
+}
+
+func stringKeys(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("error while parsing manifest, key %v is not a string", key)
+			}
+
+			var err error
+			if converted[name], err = stringKeys(item); err != nil {
+				return nil, err
+			}
+		}
+		return converted, nil
+	case []interface{}:
+		for idx, item := range v {
+			var err error
+			if v[idx], err = stringKeys(item); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
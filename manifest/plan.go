@@ -0,0 +1,304 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ptrsd/form3"
+)
+
+// Action is a change planned for a single account.
+type Action string
+
+const (
+	// ActionCreate creates a declared account which does not exist.
+	ActionCreate Action = "create"
+	// ActionUpdate patches attributes of an existing account.
+	ActionUpdate Action = "update"
+	// ActionReplace deletes and recreates an account whose changes cannot be patched, i.e. cleared attributes or a
+	// changed organisation.
+	ActionReplace Action = "replace"
+	// ActionDelete deletes an account which is not declared.
+	ActionDelete Action = "delete"
+)
+
+var actionSymbols = map[Action]string{
+	ActionCreate:  "+",
+	ActionUpdate:  "~",
+	ActionReplace: "-/+",
+	ActionDelete:  "-",
+}
+
+// Accounts manages accounts, it is implemented by form3.AccountService.
+type Accounts interface {
+	Create(createReq form3.AccountRequest) (form3.Account, error)
+	Update(updateReq form3.AccountRequest) (form3.Account, error)
+	Delete(id string, version int) error
+	List(options form3.ListOptions) ([]form3.Account, bool, error)
+}
+
+// FieldChange is a field of an account changed by a plan. From and To are JSON values, nil if the field is not set.
+type FieldChange struct {
+	Field string
+	From  interface{}
+	To    interface{}
+}
+
+// Change is a change planned for a single account.
+type Change struct {
+	Action Action
+	ID     string
+	// Current is the account as it exists in the API, nil for creates.
+	Current *form3.Account
+	// Desired is the declared account, nil for deletes.
+	Desired *form3.AccountRequest
+	Fields  []FieldChange
+}
+
+// ReplaceError is returned when an account replaced by a plan has been deleted but could not be created again. The
+// account does not exist until the plan is applied again.
+type ReplaceError struct {
+	ID  string
+	Err error
+}
+
+func (e *ReplaceError) Error() string {
+	return fmt.Sprintf("account %s has been deleted but not created again, %s", e.ID, e.Err.Error())
+}
+
+// Unwrap returns the error of the create.
+func (e *ReplaceError) Unwrap() error {
+	return e.Err
+}
+
+// Plan lists changes which bring the API to the state declared by a manifest, sorted by ID.
+type Plan struct {
+	Changes []Change
+}
+
+// NewPlan lists all accounts and compares them with the manifest.
+func NewPlan(accounts Accounts, manifest Manifest) (Plan, error) {
+	current, err := listAll(accounts)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	plan := Plan{}
+	for idx := range manifest.Accounts {
+		desired := &manifest.Accounts[idx]
+
+		account, ok := current[desired.ID]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{
+				Action:  ActionCreate,
+				ID:      desired.ID,
+				Desired: desired,
+				Fields:  diff(form3.Account{}, *desired),
+			})
+			continue
+		}
+		delete(current, desired.ID)
+
+		fields := diff(account, *desired)
+		if len(fields) == 0 {
+			continue
+		}
+
+		action := ActionUpdate
+		for _, field := range fields {
+			if field.To == nil || field.Field == "organisation_id" {
+				action = ActionReplace
+			}
+		}
+
+		plan.Changes = append(plan.Changes, Change{
+			Action:  action,
+			ID:      desired.ID,
+			Current: &account,
+			Desired: desired,
+			Fields:  fields,
+		})
+	}
+
+	if manifest.OrganisationID != "" {
+		for id, account := range current {
+			if account.OrganisationID != manifest.OrganisationID {
+				continue
+			}
+
+			account := account
+			plan.Changes = append(plan.Changes, Change{
+				Action:  ActionDelete,
+				ID:      id,
+				Current: &account,
+				Fields:  diff(account, form3.AccountRequest{}),
+			})
+		}
+	}
+
+	sort.Slice(plan.Changes, func(i, j int) bool { return plan.Changes[i].ID < plan.Changes[j].ID })
+	return plan, nil
+}
+
+// IsEmpty returns true if the API is already in the declared state.
+func (p Plan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count returns the number of changes with the action.
+func (p Plan) Count(action Action) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Apply applies changes in order. Updates and deletes use versions of accounts seen when the plan was made, so changes
+// made by others in the meantime fail with a conflict rather than being overwritten. Apply stops at the first error
+// and returns the number of applied changes. A replace which deleted the account but failed to create it again returns
+// a ReplaceError.
+func (p Plan) Apply(accounts Accounts) (int, error) {
+	for idx, change := range p.Changes {
+		if err := change.apply(accounts); err != nil {
+			return idx, fmt.Errorf("error while applying %s of account %s, %w", change.Action, change.ID, err)
+		}
+	}
+
+	return len(p.Changes), nil
+}
+
+func (c Change) apply(accounts Accounts) error {
+	switch c.Action {
+	case ActionCreate:
+		_, err := accounts.Create(*c.Desired)
+		return err
+	case ActionUpdate:
+		updateReq := *c.Desired
+		updateReq.Version = c.Current.Version
+		_, err := accounts.Update(updateReq)
+		return err
+	case ActionReplace:
+		if err := accounts.Delete(c.ID, c.Current.Version); err != nil {
+			return err
+		}
+		if _, err := accounts.Create(*c.Desired); err != nil {
+			return &ReplaceError{ID: c.ID, Err: err}
+		}
+		return nil
+	case ActionDelete:
+		return accounts.Delete(c.ID, c.Current.Version)
+	default:
+		return fmt.Errorf("unknown action %q", c.Action)
+	}
+}
+
+// WriteDiff writes a human readable diff of the plan followed by a summary line.
+//
+//	~ update ad27e265-9605-4b4b-a0e5-3003ea9cc4dc (version 0)
+//	    bank_id: "400300" => "400301"
+func (p Plan) WriteDiff(w io.Writer) error {
+	for _, change := range p.Changes {
+		header := fmt.Sprintf("%s %s %s", actionSymbols[change.Action], change.Action, change.ID)
+		if change.Current != nil {
+			header += fmt.Sprintf(" (version %d)", change.Current.Version)
+		}
+		if _, err := fmt.Fprintln(w, header); err != nil {
+			return err
+		}
+
+		for _, field := range change.Fields {
+			line := "    " + field.Field + ": "
+			switch change.Action {
+			case ActionCreate:
+				line += jsonValue(field.To)
+			case ActionDelete:
+				line += jsonValue(field.From)
+			default:
+				line += jsonValue(field.From) + " => " + jsonValue(field.To)
+			}
+
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to replace, %d to delete.\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionReplace), p.Count(ActionDelete))
+	return err
+}
+
+func listAll(accounts Accounts) (map[string]form3.Account, error) {
+	current := map[string]form3.Account{}
+	for page, hasNext := 0, true; hasNext; page++ {
+		var (
+			list []form3.Account
+			err  error
+		)
+
+		list, hasNext, err = accounts.List(form3.ListOptions{Page: page})
+		if err != nil {
+			return nil, fmt.Errorf("error while listing page %d, %w", page, err)
+		}
+
+		for _, account := range list {
+			current[account.ID] = account
+		}
+	}
+
+	return current, nil
+}
+
+// diff compares the organisation and attributes of accounts as JSON objects, so zero attributes count as not set.
+func diff(current form3.Account, desired form3.AccountRequest) []FieldChange {
+	from, to := fields(current.OrganisationID, current.Attributes), fields(desired.OrganisationID, desired.Attributes)
+
+	names := make([]string, 0, len(from)+len(to))
+	for name := range from {
+		names = append(names, name)
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []FieldChange
+	for _, name := range names {
+		if jsonValue(from[name]) != jsonValue(to[name]) {
+			changes = append(changes, FieldChange{Field: name, From: from[name], To: to[name]})
+		}
+	}
+
+	return changes
+}
+
+func fields(organisationID string, attributes form3.AccountAttributes) map[string]interface{} {
+	values := map[string]interface{}{}
+
+	// Attributes are plain strings, bools and slices of strings, so they always encode.
+	raw, _ := json.Marshal(attributes)
+	_ = json.Unmarshal(raw, &values)
+
+	if organisationID != "" {
+		values["organisation_id"] = organisationID
+	}
+
+	return values
+}
+
+func jsonValue(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(raw)
+}
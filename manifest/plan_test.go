@@ -0,0 +1,195 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ptrsd/form3"
+	"github.com/ptrsd/form3/fault"
+	"github.com/ptrsd/form3/form3test"
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+const (
+	testOrganisationID  = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+	otherOrganisationID = "5b438472-e8f7-4ce5-a189-2968e6f8f62e"
+	createdID           = "0f4b1d2c-3e5a-4b6c-8d7e-9f0a1b2c3d4e"
+	updatedID           = "1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"
+	replacedID          = "2d3e4f5a-6b7c-4d8e-9f0a-1b2c3d4e5f6a"
+	deletedID           = "3e4f5a6b-7c8d-4e9f-8a1b-2c3d4e5f6a7b"
+	unmanagedID         = "4f5a6b7c-8d9e-4f0a-9b2c-3d4e5f6a7b8c"
+)
+
+const testManifest = `
+organisation_id: ` + testOrganisationID + `
+accounts:
+  - id: ` + createdID + `
+    attributes:
+      country: GB
+  - id: ` + updatedID + `
+    attributes:
+      country: GB
+      bank_id: "400301"
+  - id: ` + replacedID + `
+    attributes:
+      country: GB
+`
+
+func TestPlan(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	givenAccount(server, updatedID, testOrganisationID, `{"country":"GB","bank_id":"400300"}`)
+	givenAccount(server, replacedID, testOrganisationID, `{"country":"GB","joint_account":true}`)
+	givenAccount(server, deletedID, testOrganisationID, `{"country":"FR"}`)
+	givenAccount(server, unmanagedID, otherOrganisationID, `{"country":"GB"}`)
+
+	accounts := form3.NewClient(nil, server.URL).AccountService
+
+	manifest, err := Read(strings.NewReader(testManifest))
+	if err != nil {
+		t.Fatalf("error while reading manifest, %s", err.Error())
+	}
+
+	t.Run("When planning then diff lists creates, updates, replaces and deletes", func(t *testing.T) {
+		plan, err := NewPlan(accounts, manifest)
+		thenEqual(t, "Error", nil, err)
+
+		out := bytes.Buffer{}
+		thenEqual(t, "WriteError", nil, plan.WriteDiff(&out))
+		thenEqual(t, "Diff", "+ create "+createdID+"\n"+
+			`    country: "GB"`+"\n"+
+			`    organisation_id: "`+testOrganisationID+`"`+"\n"+
+			"~ update "+updatedID+" (version 0)\n"+
+			`    bank_id: "400300" => "400301"`+"\n"+
+			"-/+ replace "+replacedID+" (version 0)\n"+
+			`    joint_account: true => null`+"\n"+
+			"- delete "+deletedID+" (version 0)\n"+
+			`    country: "FR"`+"\n"+
+			`    organisation_id: "`+testOrganisationID+`"`+"\n"+
+			"Plan: 1 to create, 1 to update, 1 to replace, 1 to delete.\n", out.String())
+	})
+
+	t.Run("When applying then API reaches declared state", func(t *testing.T) {
+		plan, err := NewPlan(accounts, manifest)
+		thenEqual(t, "Error", nil, err)
+
+		applied, err := plan.Apply(accounts)
+		thenEqual(t, "ApplyError", nil, err)
+		thenEqual(t, "Applied", 4, applied)
+
+		plan, err = NewPlan(accounts, manifest)
+		thenEqual(t, "PlanError", nil, err)
+		thenEqual(t, "IsEmpty", true, plan.IsEmpty())
+
+		_, ok := server.Get(form3test.AccountsPath, unmanagedID)
+		thenEqual(t, "Unmanaged", true, ok)
+	})
+
+	t.Run("When account changed after planning then apply fails with conflict", func(t *testing.T) {
+		changed, err := Read(strings.NewReader(strings.Replace(testManifest, `"400301"`, `"400302"`, 1)))
+		thenEqual(t, "ReadError", nil, err)
+
+		plan, err := NewPlan(accounts, changed)
+		thenEqual(t, "Error", nil, err)
+
+		account, err := accounts.Fetch(updatedID)
+		thenEqual(t, "FetchError", nil, err)
+		_, err = accounts.Update(form3.AccountRequest{ID: updatedID, Version: account.Version, Attributes: form3.AccountAttributes{BankID: "400303"}})
+		thenEqual(t, "UpdateError", nil, err)
+
+		applied, err := plan.Apply(accounts)
+		thenEqual(t, "Applied", 0, applied)
+		thenEqual(t, "IsConflict", true, form3.IsConflict(err))
+	})
+}
+
+func TestPlan_ApplyReplaceFailure(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	givenAccount(server, replacedID, testOrganisationID, `{"country":"GB","joint_account":true}`)
+
+	transport := &fault.Transport{Rules: []fault.Rule{{
+		Fault:       fault.Status(http.StatusServiceUnavailable, ""),
+		Probability: 1,
+		Match:       func(req *http.Request) bool { return req.Method == http.MethodPost },
+	}}}
+	accounts := form3.NewClient(&http.Client{Transport: transport}, server.URL).AccountService
+
+	manifest := Manifest{Accounts: []form3.AccountRequest{{
+		ID:             replacedID,
+		OrganisationID: testOrganisationID,
+		Attributes:     form3.AccountAttributes{Country: "GB"},
+	}}}
+
+	plan, err := NewPlan(accounts, manifest)
+	thenEqual(t, "PlanError", nil, err)
+
+	applied, err := plan.Apply(accounts)
+
+	thenEqual(t, "Applied", 0, applied)
+
+	var replaceErr *ReplaceError
+	if !errors.As(err, &replaceErr) {
+		t.Fatalf("error %v is not a ReplaceError", err)
+	}
+	var apiErr *form3.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %v is not an APIError", err)
+	}
+	thenEqual(t, "ID", replacedID, replaceErr.ID)
+	thenEqual(t, "StatusCode", http.StatusServiceUnavailable, apiErr.StatusCode)
+
+	_, ok := server.Get(form3test.AccountsPath, replacedID)
+	thenEqual(t, "Deleted", false, ok)
+}
+
+func TestRead(t *testing.T) {
+	t.Run("When manifest is JSON then it is read", func(t *testing.T) {
+		manifest, err := Read(strings.NewReader(`{"accounts":[{"id":"` + createdID + `","organisation_id":"` + testOrganisationID + `","attributes":{"alternative_bank_account_names":["Jane"]}}]}`))
+
+		thenEqual(t, "Error", nil, err)
+		thenEqual(t, "Names", []string{"Jane"}, manifest.Accounts[0].Attributes.AlternativeBankAccountNames)
+	})
+
+	t.Run("When account has no organisation then error is returned", func(t *testing.T) {
+		_, err := Read(strings.NewReader("accounts:\n  - id: " + createdID + "\n"))
+
+		thenEqual(t, "Error", "account "+createdID+" has no organisation_id", errorMessage(err))
+	})
+
+	t.Run("When account is declared twice then error is returned", func(t *testing.T) {
+		_, err := Read(strings.NewReader("organisation_id: " + testOrganisationID + "\naccounts:\n  - id: a\n  - id: a\n"))
+
+		thenEqual(t, "Error", "account a is declared more than once", errorMessage(err))
+	})
+}
+
+func givenAccount(server *form3test.Server, id, organisationID, attributes string) {
+	server.Put(form3test.AccountsPath, jsonapi.Resource{
+		ID:             id,
+		Type:           "accounts",
+		OrganisationID: organisationID,
+		Attributes:     json.RawMessage(attributes),
+	})
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func thenEqual(t *testing.T, name string, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s:\nExpected: %#v\n  Actual: %#v", name, expected, actual)
+	}
+}
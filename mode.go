@@ -0,0 +1,131 @@
+package form3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Mode guards a Client against changing resources by accident, e.g. when a script is pointed at production.
+type Mode int
+
+const (
+	// ModeNormal sends all requests.
+	ModeNormal Mode = iota
+	// ModeReadOnly rejects requests which change resources with a *ReadOnlyError.
+	ModeReadOnly
+	// ModeDryRun logs requests which change resources instead of sending them and answers them with their own body,
+	// as if the server accepted them unchanged.
+	ModeDryRun
+)
+
+var modeNames = map[Mode]string{
+	ModeNormal:   "normal",
+	ModeReadOnly: "read-only",
+	ModeDryRun:   "dry-run",
+}
+
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ParseMode parses a mode name: normal, read-only or dry-run. An empty name is the normal mode.
+func ParseMode(name string) (Mode, error) {
+	if name == "" {
+		return ModeNormal, nil
+	}
+
+	for mode, modeName := range modeNames {
+		if strings.EqualFold(name, modeName) {
+			return mode, nil
+		}
+	}
+
+	return ModeNormal, fmt.Errorf("unknown mode %q, use normal, read-only or dry-run", name)
+}
+
+// ReadOnlyError is returned by a read-only Client for requests which would change resources.
+type ReadOnlyError struct {
+	Method string
+	Path   string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("client is read-only, %s %s was not sent", e.Method, e.Path)
+}
+
+// IsReadOnly returns true if err is a ReadOnlyError.
+func IsReadOnly(err error) bool {
+	var readOnlyErr *ReadOnlyError
+	return errors.As(err, &readOnlyErr)
+}
+
+// guard applies the client mode to the request. It returns true if the request must not be sent, together with the
+// response or the error to return instead.
+func (c *Client) guard(req *http.Request) (*http.Response, bool, error) {
+	if c.Mode == ModeNormal || !changes(req.Method) {
+		return nil, false, nil
+	}
+
+	if c.Mode == ModeReadOnly {
+		return nil, true, &ReadOnlyError{Method: req.Method, Path: req.URL.Path}
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, true, err
+		}
+		req.Body.Close()
+	}
+
+	logf := log.Printf
+	if c.Logger != nil {
+		logf = c.Logger.Printf
+	}
+	logf("dry-run: %s", strings.TrimSpace(fmt.Sprintf("%s %s %s", req.Method, req.URL.RequestURI(), body)))
+
+	statusCode := http.StatusOK
+	switch {
+	case req.Method == http.MethodPost:
+		statusCode = http.StatusCreated
+	case len(body) == 0:
+		statusCode = http.StatusNoContent
+	}
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	if len(body) > 0 {
+		resp.Header.Set("Content-Type", contentType)
+	}
+
+	return resp, true, nil
+}
+
+// changes tells whether requests of the method change resources.
+func changes(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,91 @@
+package form3
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestParseMode(t *testing.T) {
+	for name, expected := range map[string]Mode{"": ModeNormal, "normal": ModeNormal, "read-only": ModeReadOnly, "DRY-RUN": ModeDryRun} {
+		mode, err := ParseMode(name)
+		thenEquals(t, assertions{
+			{actual: mode, expected: expected, name: "Mode(" + name + ")"},
+			{actual: err, expected: nil, name: "Error(" + name + ")"},
+		})
+	}
+
+	_, err := ParseMode("readonly")
+	thenEquals(t, assertions{
+		{actual: err.Error(), expected: `unknown mode "readonly", use normal, read-only or dry-run`, name: "Unknown"},
+	})
+}
+
+func TestClient_Mode(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	account, err := givenMinimalAccount(NewClient(nil, server.URL))
+	if err != nil {
+		t.Fatalf("error while generating minimal account, %s", err.Error())
+	}
+
+	t.Run("When client is read-only then changes are rejected and reads are sent", func(t *testing.T) {
+		client := NewClient(nil, server.URL)
+		client.Mode = ModeReadOnly
+
+		req, err := generateAccountRequest()
+		if err != nil {
+			t.Fatalf("error while generating account request, %s", err.Error())
+		}
+
+		_, createErr := client.AccountService.Create(req)
+		deleteErr := client.AccountService.Delete(account.ID, account.Version)
+		fetched, fetchErr := client.AccountService.Fetch(account.ID)
+
+		thenEquals(t, assertions{
+			{actual: IsReadOnly(createErr), expected: true, name: "Create.IsReadOnly"},
+			{actual: createErr.Error(), expected: "client is read-only, POST /v1/organisation/accounts was not sent", name: "Create.Message"},
+			{actual: IsReadOnly(deleteErr), expected: true, name: "Delete.IsReadOnly"},
+			{actual: fetchErr, expected: nil, name: "Fetch.Error"},
+			{actual: fetched.ID, expected: account.ID, name: "Fetch.ID"},
+			{actual: len(server.Resources(form3test.AccountsPath)), expected: 1, name: "Accounts"},
+		})
+	})
+
+	t.Run("When client is in dry-run then changes are logged and echoed", func(t *testing.T) {
+		logs := &bytes.Buffer{}
+		client := NewClient(nil, server.URL)
+		client.Mode = ModeDryRun
+		client.Logger = log.New(logs, "", 0)
+
+		req, err := generateAccountWithAttributes(AccountAttributes{Country: "FR"})
+		if err != nil {
+			t.Fatalf("error while generating account request, %s", err.Error())
+		}
+
+		created, createErr := client.AccountService.Create(req)
+		updated, updateErr := client.AccountService.Update(AccountRequest{ID: account.ID, Attributes: AccountAttributes{BankID: "400300"}})
+		deleteErr := client.AccountService.Delete(account.ID, account.Version)
+
+		stored, _ := server.Get(form3test.AccountsPath, account.ID)
+		lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+
+		thenEquals(t, assertions{
+			{actual: createErr, expected: nil, name: "Create.Error"},
+			{actual: created.ID, expected: req.ID, name: "Create.ID"},
+			{actual: created.Attributes.Country, expected: "FR", name: "Create.Country"},
+			{actual: updateErr, expected: nil, name: "Update.Error"},
+			{actual: updated.Attributes.BankID, expected: "400300", name: "Update.BankID"},
+			{actual: deleteErr, expected: nil, name: "Delete.Error"},
+			{actual: len(server.Resources(form3test.AccountsPath)), expected: 1, name: "Accounts"},
+			{actual: stored.Version, expected: 0, name: "Stored.Version"},
+			{actual: len(lines), expected: 3, name: "Logs"},
+			{actual: strings.HasPrefix(lines[0], `dry-run: POST /v1/organisation/accounts {"data":{"id":"`+req.ID), expected: true, name: "Logs.Create"},
+			{actual: lines[2], expected: "dry-run: DELETE /v1/organisation/accounts/" + account.ID + "?version=0", name: "Logs.Delete"},
+		})
+	})
+}
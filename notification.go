@@ -0,0 +1,192 @@
+package form3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// SignatureHeader is a default header carrying a signature of a notification body.
+	SignatureHeader = "X-Form3-Signature"
+
+	maxNotificationSize = 1 << 20
+)
+
+// ErrInvalidSignature is returned by SignatureVerifier when a notification is not signed correctly.
+var ErrInvalidSignature = errors.New("invalid notification signature")
+
+// Notification describes a change of a record delivered to a subscription callback.
+type Notification struct {
+	ID             string `json:"id"`
+	OrganisationID string `json:"organisation_id"`
+	EventType      string `json:"event_type"`
+	RecordType     string `json:"resource_type"`
+	Version        int    `json:"version"`
+	// Data is the changed record in its raw form. Use Decode or one of typed accessors to read it.
+	Data json.RawMessage `json:"data"`
+}
+
+// Decode decodes the changed record into v.
+func (n Notification) Decode(v interface{}) error {
+	return json.Unmarshal(n.Data, v)
+}
+
+// Account decodes the changed record as an Account.
+func (n Notification) Account() (Account, error) {
+	account := Account{}
+	err := n.Decode(&account)
+
+	return account, err
+}
+
+// Payment decodes the changed record as a Payment.
+func (n Notification) Payment() (Payment, error) {
+	payment := Payment{}
+	err := n.Decode(&payment)
+
+	return payment, err
+}
+
+// PaymentSubmission decodes the changed record as a PaymentSubmission.
+func (n Notification) PaymentSubmission() (PaymentSubmission, error) {
+	submission := PaymentSubmission{}
+	err := n.Decode(&submission)
+
+	return submission, err
+}
+
+// SignatureVerifier verifies that a notification has been sent by Form3.
+type SignatureVerifier interface {
+	Verify(r *http.Request, body []byte) error
+}
+
+// HMACVerifier verifies hex encoded HMAC-SHA256 signatures of notification bodies.
+type HMACVerifier struct {
+	Secret []byte
+	// Header carrying the signature. Default: SignatureHeader.
+	Header string
+}
+
+// Verify returns ErrInvalidSignature if the signature of body is missing or does not match.
+func (v HMACVerifier) Verify(r *http.Request, body []byte) error {
+	header := v.Header
+	if header == "" {
+		header = SignatureHeader
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(header), "sha256="))
+	if err != nil || len(signature) == 0 || !hmac.Equal(signature, hmacSum(v.Secret, body)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// HMACSignature returns a hex encoded HMAC-SHA256 signature of body.
+func HMACSignature(secret, body []byte) string {
+	return hex.EncodeToString(hmacSum(secret, body))
+}
+
+func hmacSum(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
+
+// NotificationHandlerFunc handles a notification. Returning an error makes the receiver respond with 500, so the
+// notification is delivered again.
+type NotificationHandlerFunc func(Notification) error
+
+// NotificationHandler is an http.Handler receiving notifications of subscriptions. It verifies signatures, decodes
+// notifications and dispatches them to handlers registered for the record and event type.
+type NotificationHandler struct {
+	verifier SignatureVerifier
+
+	mu       sync.RWMutex
+	handlers map[string][]NotificationHandlerFunc
+}
+
+// NewNotificationHandler creates a notification receiver. If verifier is nil then signatures are not verified.
+func NewNotificationHandler(verifier SignatureVerifier) *NotificationHandler {
+	return &NotificationHandler{verifier: verifier, handlers: map[string][]NotificationHandlerFunc{}}
+}
+
+// Handle registers fn for notifications of the record and event type. An empty record or event type matches any type.
+func (h *NotificationHandler) Handle(recordType, eventType string, fn NotificationHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := notificationKey(recordType, eventType)
+	h.handlers[key] = append(h.handlers[key], fn)
+}
+
+func (h *NotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxNotificationSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if h.verifier != nil {
+		if err := h.verifier.Verify(r, body); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	notification := Notification{}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.dispatch(notification); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *NotificationHandler) dispatch(notification Notification) error {
+	h.mu.RLock()
+	var handlers []NotificationHandlerFunc
+	seen := map[string]bool{}
+	for _, key := range []string{
+		notificationKey(notification.RecordType, notification.EventType),
+		notificationKey(notification.RecordType, ""),
+		notificationKey("", notification.EventType),
+		notificationKey("", ""),
+	} {
+		if !seen[key] {
+			seen[key] = true
+			handlers = append(handlers, h.handlers[key]...)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(notification); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func notificationKey(recordType, eventType string) string {
+	return recordType + "/" + eventType
+}
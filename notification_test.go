@@ -0,0 +1,114 @@
+package form3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testNotification = `{"id":"1","organisation_id":"2","event_type":"updated","resource_type":"accounts","version":1,` +
+	`"data":{"id":"3","type":"accounts","version":1,"attributes":{"country":"GB"}}}`
+
+func TestNotificationHandler(t *testing.T) {
+	secret := []byte("secret")
+
+	t.Run("When notification is signed then it is dispatched to matching handlers", func(t *testing.T) {
+		handler := NewNotificationHandler(HMACVerifier{Secret: secret})
+
+		var received []Account
+		handler.Handle(RecordTypeAccounts, EventTypeUpdated, func(n Notification) error {
+			account, err := n.Account()
+			received = append(received, account)
+			return err
+		})
+		handler.Handle(RecordTypeAccounts, "", func(n Notification) error {
+			account, err := n.Account()
+			received = append(received, account)
+			return err
+		})
+		handler.Handle(RecordTypePayments, "", func(n Notification) error {
+			t.Errorf("payments handler should not be called")
+			return nil
+		})
+
+		resp := whenNotifying(handler, testNotification, HMACSignature(secret, []byte(testNotification)))
+
+		thenEquals(t, assertions{
+			{actual: resp.Code, expected: http.StatusOK, name: "StatusCode"},
+			{actual: len(received), expected: 2, name: "Dispatched"},
+			{actual: received[0].Attributes.Country, expected: "GB", name: "Account.Country"},
+		})
+	})
+
+	t.Run("When signature does not match then notification is rejected", func(t *testing.T) {
+		handler := NewNotificationHandler(HMACVerifier{Secret: secret})
+		handler.Handle("", "", func(n Notification) error {
+			t.Errorf("handler should not be called")
+			return nil
+		})
+
+		resp := whenNotifying(handler, testNotification, HMACSignature([]byte("other"), []byte(testNotification)))
+
+		thenEquals(t, assertions{
+			{actual: resp.Code, expected: http.StatusUnauthorized, name: "StatusCode"},
+		})
+	})
+
+	t.Run("When signature is upper case hex then notification is accepted", func(t *testing.T) {
+		handler := NewNotificationHandler(HMACVerifier{Secret: secret})
+		handler.Handle("", "", func(n Notification) error {
+			return nil
+		})
+
+		resp := whenNotifying(handler, testNotification, strings.ToUpper(HMACSignature(secret, []byte(testNotification))))
+
+		thenEquals(t, assertions{
+			{actual: resp.Code, expected: http.StatusOK, name: "StatusCode"},
+		})
+	})
+
+	t.Run("When signature is not hex then notification is rejected", func(t *testing.T) {
+		handler := NewNotificationHandler(HMACVerifier{Secret: secret})
+
+		resp := whenNotifying(handler, testNotification, "not-hex")
+
+		thenEquals(t, assertions{
+			{actual: resp.Code, expected: http.StatusUnauthorized, name: "StatusCode"},
+		})
+	})
+
+	t.Run("When handler fails then notification is not acknowledged", func(t *testing.T) {
+		handler := NewNotificationHandler(nil)
+		handler.Handle("", "", func(n Notification) error {
+			return errors.New("not now")
+		})
+
+		resp := whenNotifying(handler, testNotification, "")
+
+		thenEquals(t, assertions{
+			{actual: resp.Code, expected: http.StatusInternalServerError, name: "StatusCode"},
+		})
+	})
+
+	t.Run("When body is not a notification then bad request is returned", func(t *testing.T) {
+		resp := whenNotifying(NewNotificationHandler(nil), "not json", "")
+
+		thenEquals(t, assertions{
+			{actual: resp.Code, expected: http.StatusBadRequest, name: "StatusCode"},
+		})
+	})
+}
+
+func whenNotifying(handler http.Handler, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/notifications", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	return resp
+}
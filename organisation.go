@@ -0,0 +1,93 @@
+package form3
+
+const (
+	organisationUnitsBasePath = "/v1/organisation/units"
+	organisationType          = "organisations"
+)
+
+// Organisation describes an organisation unit registered in Form3.
+type Organisation struct {
+	// ID is a mandatory, UUID version 4 field. It identifies organisation within a system.
+	ID string `json:"id,omitempty"`
+	// OrganisationID is an optional UUID version 4 field. It identifies a parent organisation.
+	OrganisationID string                 `json:"organisation_id,omitempty"`
+	CreatedOn      string                 `json:"created_on,omitempty"`
+	ModifiedOn     string                 `json:"modified_on,omitempty"`
+	Type           string                 `json:"type,omitempty"`
+	Version        int                    `json:"version,omitempty"`
+	Attributes     OrganisationAttributes `json:"attributes,omitempty"`
+}
+
+// OrganisationAttributes describes attributes of an organisation.
+type OrganisationAttributes struct {
+	// Name is a mandatory name of the organisation.
+	Name    string   `json:"name,omitempty"`
+	Address []string `json:"address,omitempty"`
+	City    string   `json:"city,omitempty"`
+	// Country is an ISO 3166-1 code country code.
+	Country string `json:"country,omitempty"`
+}
+
+// OrganisationRequest describes an organisation to be created or updated.
+type OrganisationRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies organisation within a system.
+	ID             string `json:"id,omitempty"`
+	OrganisationID string `json:"organisation_id,omitempty"`
+	Type           string `json:"type,omitempty"`
+	// Version is mandatory for updates and must match the current version of the organisation.
+	Version    int                    `json:"version"`
+	Attributes OrganisationAttributes `json:"attributes"`
+}
+
+type OrganisationService struct {
+	client *Client
+}
+
+// Create a new organisation. Returns Organisation or an error for network problem, and for non-2xx server statuses.
+func (o *OrganisationService) Create(createReq OrganisationRequest) (Organisation, error) {
+	if createReq.Type == "" {
+		createReq.Type = organisationType
+	}
+
+	organisation := Organisation{}
+	err := o.client.createResource(organisationUnitsBasePath, createReq, &organisation)
+
+	return organisation, err
+}
+
+// Fetch an Organisation based on ID. Returns an organisation or an error for network problem, and for non-2xx server
+// statuses.
+func (o *OrganisationService) Fetch(id string) (Organisation, error) {
+	organisation := Organisation{}
+	err := o.client.fetchResource(resourcePath(organisationUnitsBasePath, id), &organisation)
+
+	return organisation, err
+}
+
+// List organisations. Accepts pagination options as an argument. Returns list of organisations, true if there are more
+// pages with organisations or an error for network problem, and for non-2xx server statuses.
+func (o *OrganisationService) List(options ListOptions) ([]Organisation, bool, error) {
+	var organisations []Organisation
+	hasNext, err := o.client.listResources(organisationUnitsBasePath, pagingQuery(options), &organisations)
+
+	return organisations, hasNext, err
+}
+
+// Update attributes of an organisation. Only non-empty attributes are changed. The request version must match the
+// current version of the organisation. Returns the updated organisation or an error for network problem, and for
+// non-2xx server statuses.
+func (o *OrganisationService) Update(updateReq OrganisationRequest) (Organisation, error) {
+	if updateReq.Type == "" {
+		updateReq.Type = organisationType
+	}
+
+	organisation := Organisation{}
+	err := o.client.updateResource(resourcePath(organisationUnitsBasePath, updateReq.ID), updateReq, &organisation)
+
+	return organisation, err
+}
+
+// Delete an organisation. Returns error for network problem, and for non-2xx server statuses.
+func (o *OrganisationService) Delete(id string, version int) error {
+	return o.client.deleteResource(resourcePath(organisationUnitsBasePath, id), version)
+}
@@ -0,0 +1,134 @@
+package form3
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestOrganisationService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	t.Run("When creating organisation then it can be fetched", func(t *testing.T) {
+		organisation, err := givenOrganisation(client, "Acme Ltd")
+		if err != nil {
+			t.Fatalf("error while creating organisation, %s", err.Error())
+		}
+
+		fetched, err := client.OrganisationService.Fetch(organisation.ID)
+		if err != nil {
+			t.Fatalf("fetch organisation returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: fetched, expected: organisation, name: "Organisation"},
+			{actual: fetched.Type, expected: organisationType, name: "Type"},
+		})
+		thenNotEmpty(t, assertions{
+			{actual: fetched.CreatedOn, name: "CreatedOn"},
+		})
+	})
+
+	t.Run("When updating organisation then attributes are changed and version is incremented", func(t *testing.T) {
+		organisation, err := givenOrganisation(client, "Old Name")
+		if err != nil {
+			t.Fatalf("error while creating organisation, %s", err.Error())
+		}
+
+		updated, err := client.OrganisationService.Update(OrganisationRequest{
+			ID:         organisation.ID,
+			Version:    organisation.Version,
+			Attributes: OrganisationAttributes{Name: "New Name"},
+		})
+		if err != nil {
+			t.Fatalf("update organisation returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: updated.Attributes.Name, expected: "New Name", name: "Name"},
+			{actual: updated.Attributes.Country, expected: "GB", name: "Country"},
+			{actual: updated.Version, expected: organisation.Version + 1, name: "Version"},
+		})
+	})
+
+	t.Run("When updating organisation with stale version then error", func(t *testing.T) {
+		organisation, err := givenOrganisation(client, "Stale")
+		if err != nil {
+			t.Fatalf("error while creating organisation, %s", err.Error())
+		}
+
+		_, err = client.OrganisationService.Update(OrganisationRequest{ID: organisation.ID, Version: 3})
+		if err == nil {
+			t.Fatalf("update with stale version should return error")
+		}
+
+		thenEquals(t, assertions{
+			{actual: err.Error(), expected: "invalid version", name: "VersionError"},
+		})
+	})
+
+	t.Run("When deleting organisation then it does not exist", func(t *testing.T) {
+		organisation, err := givenOrganisation(client, "Deleted")
+		if err != nil {
+			t.Fatalf("error while creating organisation, %s", err.Error())
+		}
+
+		if err := client.OrganisationService.Delete(organisation.ID, organisation.Version); err != nil {
+			t.Fatalf("delete organisation returned with error %v", err.Error())
+		}
+
+		_, err = client.OrganisationService.Fetch(organisation.ID)
+		if err == nil {
+			t.Fatalf("fetch of deleted organisation should return error")
+		}
+
+		thenEquals(t, assertions{
+			{actual: err.Error(), expected: fmt.Sprintf("record %s does not exist", organisation.ID), name: "NotExistingError"},
+		})
+	})
+
+	t.Run("When listing organisations with page size then pages are returned", func(t *testing.T) {
+		server := form3test.NewServer()
+		defer server.Close()
+
+		client := NewClient(nil, server.URL)
+		for idx := 0; idx < 3; idx++ {
+			if _, err := givenOrganisation(client, fmt.Sprintf("Org %d", idx)); err != nil {
+				t.Fatalf("error while creating organisation, %s", err.Error())
+			}
+		}
+
+		first, hasNext, err := client.OrganisationService.List(ListOptions{PageSize: 2})
+		if err != nil {
+			t.Fatalf("list organisations returned with error %v", err.Error())
+		}
+
+		second, hasMore, err := client.OrganisationService.List(ListOptions{PageSize: 2, Page: 1})
+		if err != nil {
+			t.Fatalf("list organisations returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: len(first), expected: 2, name: "FirstPageLength"},
+			{actual: hasNext, expected: true, name: "FirstPageHasNext"},
+			{actual: len(second), expected: 1, name: "SecondPageLength"},
+			{actual: hasMore, expected: false, name: "SecondPageHasNext"},
+		})
+	})
+}
+
+func givenOrganisation(client *Client, name string) (Organisation, error) {
+	id, err := generateRandomUUID()
+	if err != nil {
+		return Organisation{}, err
+	}
+
+	return client.OrganisationService.Create(OrganisationRequest{
+		ID:         id,
+		Attributes: OrganisationAttributes{Name: name, Country: "GB"},
+	})
+}
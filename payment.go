@@ -0,0 +1,206 @@
+package form3
+
+import (
+	"net/url"
+
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+const (
+	transactionPaymentsBasePath = "/v1/transaction/payments"
+	paymentSubmissionsPath      = "submissions"
+	paymentType                 = "payments"
+	paymentSubmissionType       = "payment_submissions"
+)
+
+// PaymentScheme identifies a clearing scheme used to send a payment.
+type PaymentScheme string
+
+const (
+	PaymentSchemeFPS         PaymentScheme = "FPS"
+	PaymentSchemeBacs        PaymentScheme = "Bacs"
+	PaymentSchemeSEPAInstant PaymentScheme = "SEPAINSTANT"
+	PaymentSchemeSEPACT      PaymentScheme = "SEPACT"
+)
+
+// SubmissionStatus describes progress of a submission through a payment scheme.
+type SubmissionStatus string
+
+const (
+	SubmissionStatusAccepted           SubmissionStatus = "accepted"
+	SubmissionStatusValidationPending  SubmissionStatus = "validation_pending"
+	SubmissionStatusQueuedForDelivery  SubmissionStatus = "queued_for_delivery"
+	SubmissionStatusReleasedToGateway  SubmissionStatus = "released_to_gateway"
+	SubmissionStatusDeliveryConfirmed  SubmissionStatus = "delivery_confirmed"
+	SubmissionStatusDeliveryFailed     SubmissionStatus = "delivery_failed"
+	SubmissionStatusValidationFailed   SubmissionStatus = "validation_failed"
+	SubmissionStatusSubmissionRejected SubmissionStatus = "submission_rejected"
+)
+
+// Payment describes an outbound payment.
+type Payment struct {
+	// ID is a mandatory, UUID version 4 field. It identifies payment within a system.
+	ID string `json:"id,omitempty"`
+	// OrganisationID is a mandatory UUID version 4 field. It identifies organisation by which the payment has been
+	// created.
+	OrganisationID string                `json:"organisation_id,omitempty"`
+	CreatedOn      string                `json:"created_on,omitempty"`
+	ModifiedOn     string                `json:"modified_on,omitempty"`
+	Type           string                `json:"type,omitempty"`
+	Version        int                   `json:"version,omitempty"`
+	Attributes     PaymentAttributes     `json:"attributes,omitempty"`
+	Relationships  jsonapi.Relationships `json:"relationships,omitempty"`
+}
+
+// PaymentAttributes describes attributes of a payment.
+type PaymentAttributes struct {
+	// Amount is a mandatory decimal amount of the payment, e.g. "100.21".
+	Amount string `json:"amount,omitempty"`
+	// Currency is a mandatory, ISO 4217 currency code.
+	Currency         string        `json:"currency,omitempty"`
+	DebtorParty      *PaymentParty `json:"debtor_party,omitempty"`
+	BeneficiaryParty *PaymentParty `json:"beneficiary_party,omitempty"`
+	// Reference is a payment reference presented to the beneficiary.
+	Reference            string        `json:"reference,omitempty"`
+	EndToEndReference    string        `json:"end_to_end_reference,omitempty"`
+	NumericReference     string        `json:"numeric_reference,omitempty"`
+	PaymentPurpose       string        `json:"payment_purpose,omitempty"`
+	PaymentScheme        PaymentScheme `json:"payment_scheme,omitempty"`
+	PaymentType          string        `json:"payment_type,omitempty"`
+	ProcessingDate       string        `json:"processing_date,omitempty"`
+	SchemePaymentType    string        `json:"scheme_payment_type,omitempty"`
+	SchemePaymentSubType string        `json:"scheme_payment_sub_type,omitempty"`
+}
+
+// PaymentParty describes a debtor or a beneficiary of a payment.
+type PaymentParty struct {
+	AccountName       string   `json:"account_name,omitempty"`
+	AccountNumber     string   `json:"account_number,omitempty"`
+	AccountNumberCode string   `json:"account_number_code,omitempty"`
+	Address           []string `json:"address,omitempty"`
+	BankID            string   `json:"bank_id,omitempty"`
+	BankIDCode        string   `json:"bank_id_code,omitempty"`
+	Country           string   `json:"country,omitempty"`
+	Name              string   `json:"name,omitempty"`
+}
+
+// PaymentRequest describes a payment to be created.
+type PaymentRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies payment within a system.
+	ID string `json:"id,omitempty"`
+	// OrganisationID is a mandatory UUID version 4 field.
+	OrganisationID string            `json:"organisation_id"`
+	Type           string            `json:"type,omitempty"`
+	Attributes     PaymentAttributes `json:"attributes"`
+}
+
+// PaymentFilter narrows down a list of payments. Empty fields are not used for filtering.
+type PaymentFilter struct {
+	OrganisationID string
+	Currency       string
+	PaymentScheme  PaymentScheme
+	Reference      string
+	ProcessingDate string
+}
+
+// PaymentSubmission describes a submission of a payment to a payment scheme.
+type PaymentSubmission struct {
+	ID             string                      `json:"id,omitempty"`
+	OrganisationID string                      `json:"organisation_id,omitempty"`
+	CreatedOn      string                      `json:"created_on,omitempty"`
+	ModifiedOn     string                      `json:"modified_on,omitempty"`
+	Type           string                      `json:"type,omitempty"`
+	Version        int                         `json:"version,omitempty"`
+	Attributes     PaymentSubmissionAttributes `json:"attributes,omitempty"`
+	Relationships  jsonapi.Relationships       `json:"relationships,omitempty"`
+}
+
+// PaymentSubmissionAttributes describes status of a payment submission.
+type PaymentSubmissionAttributes struct {
+	Status             SubmissionStatus `json:"status,omitempty"`
+	StatusReason       string           `json:"status_reason,omitempty"`
+	SubmissionDatetime string           `json:"submission_datetime,omitempty"`
+}
+
+type paymentSubmissionRequest struct {
+	ID             string `json:"id"`
+	OrganisationID string `json:"organisation_id,omitempty"`
+	Type           string `json:"type"`
+}
+
+type PaymentService struct {
+	client *Client
+}
+
+// Create a new payment. Returns Payment or an error for network problem, and for non-2xx server statuses. Creating a
+// payment does not send it, it is sent by creating a submission.
+func (p *PaymentService) Create(createReq PaymentRequest) (Payment, error) {
+	if createReq.Type == "" {
+		createReq.Type = paymentType
+	}
+	if createReq.OrganisationID == "" {
+		createReq.OrganisationID = p.client.OrganisationID
+	}
+
+	payment := Payment{}
+	err := p.client.createResource(transactionPaymentsBasePath, createReq, &payment)
+
+	return payment, err
+}
+
+// Fetch a Payment based on ID. Returns a payment or an error for network problem, and for non-2xx server statuses.
+func (p *PaymentService) Fetch(id string) (Payment, error) {
+	payment := Payment{}
+	err := p.client.fetchResource(resourcePath(transactionPaymentsBasePath, id), &payment)
+
+	return payment, err
+}
+
+// List payments matching the filter, Client.OrganisationID filters payments unless the filter sets an organisation.
+// Accepts pagination options and a filter as arguments. Returns list of payments, true if there are more pages with
+// payments or an error for network problem, and for non-2xx server statuses.
+func (p *PaymentService) List(options ListOptions, filter PaymentFilter) ([]Payment, bool, error) {
+	if filter.OrganisationID == "" {
+		filter.OrganisationID = p.client.OrganisationID
+	}
+
+	query := pagingQuery(options)
+	setFilter(query, "organisation_id", filter.OrganisationID)
+	setFilter(query, "currency", filter.Currency)
+	setFilter(query, "payment_scheme", string(filter.PaymentScheme))
+	setFilter(query, "reference", filter.Reference)
+	setFilter(query, "processing_date", filter.ProcessingDate)
+
+	var payments []Payment
+	hasNext, err := p.client.listResources(transactionPaymentsBasePath, query, &payments)
+
+	return payments, hasNext, err
+}
+
+// CreateSubmission submits a payment to its payment scheme. The submission ID is a mandatory, UUID version 4 value.
+// The submission is created in Client.OrganisationID, if it is set. Returns the submission or an error for network
+// problem, and for non-2xx server statuses.
+func (p *PaymentService) CreateSubmission(paymentID, submissionID string) (PaymentSubmission, error) {
+	submissionReq := paymentSubmissionRequest{ID: submissionID, OrganisationID: p.client.OrganisationID, Type: paymentSubmissionType}
+
+	submission := PaymentSubmission{}
+	err := p.client.createResource(resourcePath(transactionPaymentsBasePath, paymentID, paymentSubmissionsPath), submissionReq, &submission)
+
+	return submission, err
+}
+
+// FetchSubmission returns a submission of a payment with its current status or an error for network problem, and for
+// non-2xx server statuses.
+func (p *PaymentService) FetchSubmission(paymentID, submissionID string) (PaymentSubmission, error) {
+	submission := PaymentSubmission{}
+	err := p.client.fetchResource(resourcePath(transactionPaymentsBasePath, paymentID, paymentSubmissionsPath, submissionID), &submission)
+
+	return submission, err
+}
+
+// setFilter adds a filter[name] query parameter if the value is not empty.
+func setFilter(query url.Values, name, value string) {
+	if value != "" {
+		query.Set("filter["+name+"]", value)
+	}
+}
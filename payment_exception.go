@@ -0,0 +1,354 @@
+package form3
+
+import "github.com/ptrsd/form3/jsonapi"
+
+const (
+	paymentReturnsPath   = "returns"
+	paymentReversalsPath = "reversals"
+	paymentRecallsPath   = "recalls"
+	admissionsPath       = "admissions"
+
+	paymentReturnType             = "returns"
+	paymentReversalType           = "reversals"
+	paymentRecallType             = "recalls"
+	paymentReturnSubmissionType   = "return_submissions"
+	paymentReversalSubmissionType = "reversal_submissions"
+	paymentRecallSubmissionType   = "recall_submissions"
+	paymentReturnAdmissionType    = "return_admissions"
+	paymentReversalAdmissionType  = "reversal_admissions"
+	paymentRecallAdmissionType    = "recall_admissions"
+)
+
+// ReturnReasonCode is an ISO 20022 reason code of a payment return.
+type ReturnReasonCode string
+
+const (
+	ReturnReasonIncorrectAccountNumber  ReturnReasonCode = "AC01"
+	ReturnReasonClosedAccount           ReturnReasonCode = "AC04"
+	ReturnReasonBlockedAccount          ReturnReasonCode = "AC06"
+	ReturnReasonDuplication             ReturnReasonCode = "AM05"
+	ReturnReasonInconsistentWithEndUser ReturnReasonCode = "BE01"
+	ReturnReasonFollowingRecall         ReturnReasonCode = "FOCR"
+	ReturnReasonAccountHolderDeceased   ReturnReasonCode = "MD07"
+	ReturnReasonRegulatory              ReturnReasonCode = "RR04"
+)
+
+// RecallReasonCode is an ISO 20022 reason code of a payment recall.
+type RecallReasonCode string
+
+const (
+	RecallReasonWrongAmount      RecallReasonCode = "AM09"
+	RecallReasonCustomerRequest  RecallReasonCode = "CUST"
+	RecallReasonDuplicate        RecallReasonCode = "DUPL"
+	RecallReasonFraud            RecallReasonCode = "FRAD"
+	RecallReasonTechnicalProblem RecallReasonCode = "TECH"
+)
+
+// PaymentReturn describes a return of a received payment to its sender.
+type PaymentReturn struct {
+	ID             string                  `json:"id,omitempty"`
+	OrganisationID string                  `json:"organisation_id,omitempty"`
+	CreatedOn      string                  `json:"created_on,omitempty"`
+	ModifiedOn     string                  `json:"modified_on,omitempty"`
+	Type           string                  `json:"type,omitempty"`
+	Version        int                     `json:"version,omitempty"`
+	Attributes     PaymentReturnAttributes `json:"attributes,omitempty"`
+	Relationships  jsonapi.Relationships   `json:"relationships,omitempty"`
+}
+
+// PaymentReturnAttributes describes why and how much of a payment is returned.
+type PaymentReturnAttributes struct {
+	// ReturnCode is a mandatory reason of the return.
+	ReturnCode ReturnReasonCode `json:"return_code,omitempty"`
+	Amount     string           `json:"amount,omitempty"`
+	Currency   string           `json:"currency,omitempty"`
+}
+
+// PaymentReturnRequest describes a return to be created.
+type PaymentReturnRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies return within a system.
+	ID             string                  `json:"id,omitempty"`
+	OrganisationID string                  `json:"organisation_id"`
+	Type           string                  `json:"type,omitempty"`
+	Attributes     PaymentReturnAttributes `json:"attributes"`
+}
+
+// PaymentReversal describes a reversal of a sent payment.
+type PaymentReversal struct {
+	ID             string                `json:"id,omitempty"`
+	OrganisationID string                `json:"organisation_id,omitempty"`
+	CreatedOn      string                `json:"created_on,omitempty"`
+	ModifiedOn     string                `json:"modified_on,omitempty"`
+	Type           string                `json:"type,omitempty"`
+	Version        int                   `json:"version,omitempty"`
+	Relationships  jsonapi.Relationships `json:"relationships,omitempty"`
+}
+
+// PaymentReversalRequest describes a reversal to be created.
+type PaymentReversalRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies reversal within a system.
+	ID             string `json:"id,omitempty"`
+	OrganisationID string `json:"organisation_id"`
+	Type           string `json:"type,omitempty"`
+}
+
+// PaymentRecall describes a request to the receiving bank to send back a payment.
+type PaymentRecall struct {
+	ID             string                  `json:"id,omitempty"`
+	OrganisationID string                  `json:"organisation_id,omitempty"`
+	CreatedOn      string                  `json:"created_on,omitempty"`
+	ModifiedOn     string                  `json:"modified_on,omitempty"`
+	Type           string                  `json:"type,omitempty"`
+	Version        int                     `json:"version,omitempty"`
+	Attributes     PaymentRecallAttributes `json:"attributes,omitempty"`
+	Relationships  jsonapi.Relationships   `json:"relationships,omitempty"`
+}
+
+// PaymentRecallAttributes describes why a payment is recalled.
+type PaymentRecallAttributes struct {
+	// ReasonCode is a mandatory reason of the recall.
+	ReasonCode RecallReasonCode `json:"reason_code,omitempty"`
+	Reason     string           `json:"reason,omitempty"`
+}
+
+// PaymentRecallRequest describes a recall to be created.
+type PaymentRecallRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies recall within a system.
+	ID             string                  `json:"id,omitempty"`
+	OrganisationID string                  `json:"organisation_id"`
+	Type           string                  `json:"type,omitempty"`
+	Attributes     PaymentRecallAttributes `json:"attributes"`
+}
+
+// PaymentAdmission describes how an exception received from a payment scheme has been admitted.
+type PaymentAdmission struct {
+	ID             string                     `json:"id,omitempty"`
+	OrganisationID string                     `json:"organisation_id,omitempty"`
+	CreatedOn      string                     `json:"created_on,omitempty"`
+	ModifiedOn     string                     `json:"modified_on,omitempty"`
+	Type           string                     `json:"type,omitempty"`
+	Version        int                        `json:"version,omitempty"`
+	Attributes     PaymentAdmissionAttributes `json:"attributes,omitempty"`
+	Relationships  jsonapi.Relationships      `json:"relationships,omitempty"`
+}
+
+// PaymentAdmissionAttributes describes status of an admission.
+type PaymentAdmissionAttributes struct {
+	Status            string `json:"status,omitempty"`
+	StatusReason      string `json:"status_reason,omitempty"`
+	AdmissionDatetime string `json:"admission_datetime,omitempty"`
+}
+
+// PaymentAdmissionRequest describes an admission to be created.
+type PaymentAdmissionRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies admission within a system.
+	ID             string                     `json:"id,omitempty"`
+	OrganisationID string                     `json:"organisation_id"`
+	Type           string                     `json:"type,omitempty"`
+	Attributes     PaymentAdmissionAttributes `json:"attributes"`
+}
+
+// PaymentReturnService manages returns of payments. Returns are sub-resources of a payment.
+type PaymentReturnService struct {
+	client *Client
+}
+
+// Create a return of the payment. Requests without an organisation ID are created in Client.OrganisationID. Returns
+// PaymentReturn or an error for network problem, and for non-2xx server statuses.
+func (s *PaymentReturnService) Create(paymentID string, returnReq PaymentReturnRequest) (PaymentReturn, error) {
+	if returnReq.Type == "" {
+		returnReq.Type = paymentReturnType
+	}
+	if returnReq.OrganisationID == "" {
+		returnReq.OrganisationID = s.client.OrganisationID
+	}
+
+	paymentReturn := PaymentReturn{}
+	err := s.client.createResource(resourcePath(transactionPaymentsBasePath, paymentID, paymentReturnsPath), returnReq, &paymentReturn)
+
+	return paymentReturn, err
+}
+
+// Fetch a return of the payment. Returns PaymentReturn or an error for network problem, and for non-2xx server
+// statuses.
+func (s *PaymentReturnService) Fetch(paymentID, returnID string) (PaymentReturn, error) {
+	paymentReturn := PaymentReturn{}
+	err := s.client.fetchResource(resourcePath(transactionPaymentsBasePath, paymentID, paymentReturnsPath, returnID), &paymentReturn)
+
+	return paymentReturn, err
+}
+
+// CreateSubmission submits a return to the payment scheme. Returns the submission or an error for network problem, and
+// for non-2xx server statuses.
+func (s *PaymentReturnService) CreateSubmission(paymentID, returnID, submissionID string) (PaymentSubmission, error) {
+	return s.client.createExceptionSubmission(paymentID, paymentReturnsPath, returnID, submissionID, paymentReturnSubmissionType)
+}
+
+// FetchSubmission returns a submission of a return with its current status or an error for network problem, and for
+// non-2xx server statuses.
+func (s *PaymentReturnService) FetchSubmission(paymentID, returnID, submissionID string) (PaymentSubmission, error) {
+	return s.client.fetchExceptionSubmission(paymentID, paymentReturnsPath, returnID, submissionID)
+}
+
+// CreateAdmission admits a return received from the payment scheme. Requests without an organisation ID are created in
+// Client.OrganisationID. Returns the admission or an error for network problem, and for non-2xx server statuses.
+func (s *PaymentReturnService) CreateAdmission(paymentID, returnID string, admissionReq PaymentAdmissionRequest) (PaymentAdmission, error) {
+	return s.client.createExceptionAdmission(paymentID, paymentReturnsPath, returnID, paymentReturnAdmissionType, admissionReq)
+}
+
+// FetchAdmission returns an admission of a return received from the payment scheme or an error for network problem,
+// and for non-2xx server statuses.
+func (s *PaymentReturnService) FetchAdmission(paymentID, returnID, admissionID string) (PaymentAdmission, error) {
+	return s.client.fetchExceptionAdmission(paymentID, paymentReturnsPath, returnID, admissionID)
+}
+
+// PaymentReversalService manages reversals of payments. Reversals are sub-resources of a payment.
+type PaymentReversalService struct {
+	client *Client
+}
+
+// Create a reversal of the payment. Requests without an organisation ID are created in Client.OrganisationID. Returns
+// PaymentReversal or an error for network problem, and for non-2xx server statuses.
+func (s *PaymentReversalService) Create(paymentID string, reversalReq PaymentReversalRequest) (PaymentReversal, error) {
+	if reversalReq.Type == "" {
+		reversalReq.Type = paymentReversalType
+	}
+	if reversalReq.OrganisationID == "" {
+		reversalReq.OrganisationID = s.client.OrganisationID
+	}
+
+	reversal := PaymentReversal{}
+	err := s.client.createResource(resourcePath(transactionPaymentsBasePath, paymentID, paymentReversalsPath), reversalReq, &reversal)
+
+	return reversal, err
+}
+
+// Fetch a reversal of the payment. Returns PaymentReversal or an error for network problem, and for non-2xx server
+// statuses.
+func (s *PaymentReversalService) Fetch(paymentID, reversalID string) (PaymentReversal, error) {
+	reversal := PaymentReversal{}
+	err := s.client.fetchResource(resourcePath(transactionPaymentsBasePath, paymentID, paymentReversalsPath, reversalID), &reversal)
+
+	return reversal, err
+}
+
+// CreateSubmission submits a reversal to the payment scheme. Returns the submission or an error for network problem,
+// and for non-2xx server statuses.
+func (s *PaymentReversalService) CreateSubmission(paymentID, reversalID, submissionID string) (PaymentSubmission, error) {
+	return s.client.createExceptionSubmission(paymentID, paymentReversalsPath, reversalID, submissionID, paymentReversalSubmissionType)
+}
+
+// FetchSubmission returns a submission of a reversal with its current status or an error for network problem, and for
+// non-2xx server statuses.
+func (s *PaymentReversalService) FetchSubmission(paymentID, reversalID, submissionID string) (PaymentSubmission, error) {
+	return s.client.fetchExceptionSubmission(paymentID, paymentReversalsPath, reversalID, submissionID)
+}
+
+// CreateAdmission admits a reversal received from the payment scheme. Requests without an organisation ID are created in
+// Client.OrganisationID. Returns the admission or an error for network problem, and for non-2xx server statuses.
+func (s *PaymentReversalService) CreateAdmission(paymentID, reversalID string, admissionReq PaymentAdmissionRequest) (PaymentAdmission, error) {
+	return s.client.createExceptionAdmission(paymentID, paymentReversalsPath, reversalID, paymentReversalAdmissionType, admissionReq)
+}
+
+// FetchAdmission returns an admission of a reversal received from the payment scheme or an error for network problem,
+// and for non-2xx server statuses.
+func (s *PaymentReversalService) FetchAdmission(paymentID, reversalID, admissionID string) (PaymentAdmission, error) {
+	return s.client.fetchExceptionAdmission(paymentID, paymentReversalsPath, reversalID, admissionID)
+}
+
+// PaymentRecallService manages recalls of payments. Recalls are sub-resources of a payment.
+type PaymentRecallService struct {
+	client *Client
+}
+
+// Create a recall of the payment. Requests without an organisation ID are created in Client.OrganisationID. Returns
+// PaymentRecall or an error for network problem, and for non-2xx server statuses.
+func (s *PaymentRecallService) Create(paymentID string, recallReq PaymentRecallRequest) (PaymentRecall, error) {
+	if recallReq.Type == "" {
+		recallReq.Type = paymentRecallType
+	}
+	if recallReq.OrganisationID == "" {
+		recallReq.OrganisationID = s.client.OrganisationID
+	}
+
+	recall := PaymentRecall{}
+	err := s.client.createResource(resourcePath(transactionPaymentsBasePath, paymentID, paymentRecallsPath), recallReq, &recall)
+
+	return recall, err
+}
+
+// Fetch a recall of the payment. Returns PaymentRecall or an error for network problem, and for non-2xx server
+// statuses.
+func (s *PaymentRecallService) Fetch(paymentID, recallID string) (PaymentRecall, error) {
+	recall := PaymentRecall{}
+	err := s.client.fetchResource(resourcePath(transactionPaymentsBasePath, paymentID, paymentRecallsPath, recallID), &recall)
+
+	return recall, err
+}
+
+// CreateSubmission submits a recall to the payment scheme. Returns the submission or an error for network problem, and
+// for non-2xx server statuses.
+func (s *PaymentRecallService) CreateSubmission(paymentID, recallID, submissionID string) (PaymentSubmission, error) {
+	return s.client.createExceptionSubmission(paymentID, paymentRecallsPath, recallID, submissionID, paymentRecallSubmissionType)
+}
+
+// FetchSubmission returns a submission of a recall with its current status or an error for network problem, and for
+// non-2xx server statuses.
+func (s *PaymentRecallService) FetchSubmission(paymentID, recallID, submissionID string) (PaymentSubmission, error) {
+	return s.client.fetchExceptionSubmission(paymentID, paymentRecallsPath, recallID, submissionID)
+}
+
+// CreateAdmission admits a recall received from the payment scheme. Requests without an organisation ID are created in
+// Client.OrganisationID. Returns the admission or an error for network problem, and for non-2xx server statuses.
+func (s *PaymentRecallService) CreateAdmission(paymentID, recallID string, admissionReq PaymentAdmissionRequest) (PaymentAdmission, error) {
+	return s.client.createExceptionAdmission(paymentID, paymentRecallsPath, recallID, paymentRecallAdmissionType, admissionReq)
+}
+
+// FetchAdmission returns an admission of a recall received from the payment scheme or an error for network problem,
+// and for non-2xx server statuses.
+func (s *PaymentRecallService) FetchAdmission(paymentID, recallID, admissionID string) (PaymentAdmission, error) {
+	return s.client.fetchExceptionAdmission(paymentID, paymentRecallsPath, recallID, admissionID)
+}
+
+func (c *Client) createExceptionSubmission(paymentID, exceptionPath, exceptionID, submissionID, submissionType string) (PaymentSubmission, error) {
+	submissionReq := paymentSubmissionRequest{ID: submissionID, OrganisationID: c.OrganisationID, Type: submissionType}
+	path := resourcePath(transactionPaymentsBasePath, paymentID, exceptionPath, exceptionID, paymentSubmissionsPath)
+
+	submission := PaymentSubmission{}
+	err := c.createResource(path, submissionReq, &submission)
+
+	return submission, err
+}
+
+func (c *Client) fetchExceptionSubmission(paymentID, exceptionPath, exceptionID, submissionID string) (PaymentSubmission, error) {
+	path := resourcePath(transactionPaymentsBasePath, paymentID, exceptionPath, exceptionID, paymentSubmissionsPath, submissionID)
+
+	submission := PaymentSubmission{}
+	err := c.fetchResource(path, &submission)
+
+	return submission, err
+}
+
+func (c *Client) createExceptionAdmission(paymentID, exceptionPath, exceptionID, admissionType string, admissionReq PaymentAdmissionRequest) (PaymentAdmission, error) {
+	if admissionReq.Type == "" {
+		admissionReq.Type = admissionType
+	}
+	if admissionReq.OrganisationID == "" {
+		admissionReq.OrganisationID = c.OrganisationID
+	}
+	path := resourcePath(transactionPaymentsBasePath, paymentID, exceptionPath, exceptionID, admissionsPath)
+
+	admission := PaymentAdmission{}
+	err := c.createResource(path, admissionReq, &admission)
+
+	return admission, err
+}
+
+func (c *Client) fetchExceptionAdmission(paymentID, exceptionPath, exceptionID, admissionID string) (PaymentAdmission, error) {
+	path := resourcePath(transactionPaymentsBasePath, paymentID, exceptionPath, exceptionID, admissionsPath, admissionID)
+
+	admission := PaymentAdmission{}
+	err := c.fetchResource(path, &admission)
+
+	return admission, err
+}
@@ -0,0 +1,260 @@
+package form3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+func TestPaymentReturnService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	payment, err := givenPayment(client, "GBP", PaymentSchemeFPS)
+	if err != nil {
+		t.Fatalf("error while creating payment, %s", err.Error())
+	}
+
+	returnID, submissionID, err := generateIDs()
+	if err != nil {
+		t.Fatalf("error while generating ids, %s", err.Error())
+	}
+
+	t.Run("When returning payment then return is stored with reason code", func(t *testing.T) {
+		created, err := client.PaymentReturnService.Create(payment.ID, PaymentReturnRequest{
+			ID:             returnID,
+			OrganisationID: payment.OrganisationID,
+			Attributes:     PaymentReturnAttributes{ReturnCode: ReturnReasonClosedAccount},
+		})
+		if err != nil {
+			t.Fatalf("create return returned with error %v", err.Error())
+		}
+
+		fetched, err := client.PaymentReturnService.Fetch(payment.ID, returnID)
+		if err != nil {
+			t.Fatalf("fetch return returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: created.Type, expected: paymentReturnType, name: "Type"},
+			{actual: fetched.Attributes.ReturnCode, expected: ReturnReasonClosedAccount, name: "ReturnCode"},
+		})
+	})
+
+	t.Run("When submitting return then submission is stored under the return", func(t *testing.T) {
+		submission, err := client.PaymentReturnService.CreateSubmission(payment.ID, returnID, submissionID)
+		if err != nil {
+			t.Fatalf("create return submission returned with error %v", err.Error())
+		}
+
+		fetched, err := client.PaymentReturnService.FetchSubmission(payment.ID, returnID, submissionID)
+		if err != nil {
+			t.Fatalf("fetch return submission returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: submission.Type, expected: paymentReturnSubmissionType, name: "Type"},
+			{actual: fetched.ID, expected: submissionID, name: "ID"},
+		})
+	})
+	t.Run("When admitting return then admission is stored under the return", func(t *testing.T) {
+		admissionID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		created, err := client.PaymentReturnService.CreateAdmission(payment.ID, returnID, PaymentAdmissionRequest{
+			ID:         admissionID,
+			Attributes: PaymentAdmissionAttributes{Status: "confirmed"},
+		})
+		if err != nil {
+			t.Fatalf("create return admission returned with error %v", err.Error())
+		}
+
+		fetched, err := client.PaymentReturnService.FetchAdmission(payment.ID, returnID, admissionID)
+		if err != nil {
+			t.Fatalf("fetch return admission returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: created.Type, expected: paymentReturnAdmissionType, name: "Type"},
+			{actual: fetched.ID, expected: admissionID, name: "ID"},
+			{actual: fetched.Attributes.Status, expected: "confirmed", name: "Status"},
+		})
+	})
+
+	t.Run("When client is scoped to organisation then return and its submission are created in it", func(t *testing.T) {
+		scopedReturnID, scopedSubmissionID, err := generateIDs()
+		if err != nil {
+			t.Fatalf("error while generating ids, %s", err.Error())
+		}
+
+		scoped := client.ForOrganisation(payment.OrganisationID)
+		created, err := scoped.PaymentReturnService.Create(payment.ID, PaymentReturnRequest{
+			ID:         scopedReturnID,
+			Attributes: PaymentReturnAttributes{ReturnCode: ReturnReasonDuplication},
+		})
+		if err != nil {
+			t.Fatalf("create return returned with error %v", err.Error())
+		}
+
+		submission, err := scoped.PaymentReturnService.CreateSubmission(payment.ID, scopedReturnID, scopedSubmissionID)
+		if err != nil {
+			t.Fatalf("create return submission returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: created.OrganisationID, expected: payment.OrganisationID, name: "OrganisationID"},
+			{actual: submission.OrganisationID, expected: payment.OrganisationID, name: "Submission.OrganisationID"},
+		})
+	})
+}
+
+func TestPaymentReversalService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	payment, err := givenPayment(client, "GBP", PaymentSchemeBacs)
+	if err != nil {
+		t.Fatalf("error while creating payment, %s", err.Error())
+	}
+
+	reversalID, admissionID, err := generateIDs()
+	if err != nil {
+		t.Fatalf("error while generating ids, %s", err.Error())
+	}
+
+	t.Run("When reversing payment then reversal admission can be fetched", func(t *testing.T) {
+		if _, err := client.PaymentReversalService.Create(payment.ID, PaymentReversalRequest{ID: reversalID, OrganisationID: payment.OrganisationID}); err != nil {
+			t.Fatalf("create reversal returned with error %v", err.Error())
+		}
+
+		server.Put(resourcePath(transactionPaymentsBasePath, payment.ID, paymentReversalsPath, reversalID, admissionsPath), jsonapi.Resource{
+			ID:         admissionID,
+			Type:       "reversal_admissions",
+			Attributes: json.RawMessage(`{"status":"confirmed"}`),
+		})
+
+		reversal, err := client.PaymentReversalService.Fetch(payment.ID, reversalID)
+		if err != nil {
+			t.Fatalf("fetch reversal returned with error %v", err.Error())
+		}
+
+		admission, err := client.PaymentReversalService.FetchAdmission(payment.ID, reversalID, admissionID)
+		if err != nil {
+			t.Fatalf("fetch reversal admission returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: reversal.Type, expected: paymentReversalType, name: "Type"},
+			{actual: admission.Attributes.Status, expected: "confirmed", name: "AdmissionStatus"},
+		})
+	})
+	t.Run("When client is scoped to organisation then reversal is created in it", func(t *testing.T) {
+		scopedReversalID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		reversal, err := client.ForOrganisation(payment.OrganisationID).PaymentReversalService.Create(payment.ID, PaymentReversalRequest{ID: scopedReversalID})
+		if err != nil {
+			t.Fatalf("create reversal returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: reversal.OrganisationID, expected: payment.OrganisationID, name: "OrganisationID"},
+		})
+	})
+}
+
+func TestPaymentRecallService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	payment, err := givenPayment(client, "EUR", PaymentSchemeSEPAInstant)
+	if err != nil {
+		t.Fatalf("error while creating payment, %s", err.Error())
+	}
+
+	recallID, submissionID, err := generateIDs()
+	if err != nil {
+		t.Fatalf("error while generating ids, %s", err.Error())
+	}
+
+	t.Run("When recalling payment then recall and its submission are stored", func(t *testing.T) {
+		recall, err := client.PaymentRecallService.Create(payment.ID, PaymentRecallRequest{
+			ID:             recallID,
+			OrganisationID: payment.OrganisationID,
+			Attributes:     PaymentRecallAttributes{ReasonCode: RecallReasonDuplicate, Reason: "sent twice"},
+		})
+		if err != nil {
+			t.Fatalf("create recall returned with error %v", err.Error())
+		}
+
+		submission, err := client.PaymentRecallService.CreateSubmission(payment.ID, recallID, submissionID)
+		if err != nil {
+			t.Fatalf("create recall submission returned with error %v", err.Error())
+		}
+
+		fetched, err := client.PaymentRecallService.Fetch(payment.ID, recallID)
+		if err != nil {
+			t.Fatalf("fetch recall returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: fetched.Attributes, expected: recall.Attributes, name: "Attributes"},
+			{actual: fetched.Attributes.ReasonCode, expected: RecallReasonDuplicate, name: "ReasonCode"},
+			{actual: submission.Type, expected: paymentRecallSubmissionType, name: "SubmissionType"},
+		})
+	})
+
+	t.Run("When fetching not existing admission then error", func(t *testing.T) {
+		admissionID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		if _, err := client.PaymentRecallService.FetchAdmission(payment.ID, recallID, admissionID); err == nil {
+			t.Errorf("fetch of not existing admission should return error")
+		}
+	})
+	t.Run("When admitting not existing recall then error", func(t *testing.T) {
+		notExistingRecallID, admissionID, err := generateIDs()
+		if err != nil {
+			t.Fatalf("error while generating ids, %s", err.Error())
+		}
+
+		_, err = client.PaymentRecallService.CreateAdmission(payment.ID, notExistingRecallID, PaymentAdmissionRequest{ID: admissionID})
+
+		thenEquals(t, assertions{
+			{actual: IsNotFound(err), expected: true, name: "IsNotFound"},
+		})
+	})
+
+	t.Run("When client is scoped to organisation then recall is created in it", func(t *testing.T) {
+		scopedRecallID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		recall, err := client.ForOrganisation(payment.OrganisationID).PaymentRecallService.Create(payment.ID, PaymentRecallRequest{
+			ID:         scopedRecallID,
+			Attributes: PaymentRecallAttributes{ReasonCode: RecallReasonFraud},
+		})
+		if err != nil {
+			t.Fatalf("create recall returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: recall.OrganisationID, expected: payment.OrganisationID, name: "OrganisationID"},
+		})
+	})
+}
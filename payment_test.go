@@ -0,0 +1,143 @@
+package form3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+func TestPaymentService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	t.Run("When creating payment then it can be fetched with typed attributes", func(t *testing.T) {
+		payment, err := givenPayment(client, "GBP", PaymentSchemeFPS)
+		if err != nil {
+			t.Fatalf("error while creating payment, %s", err.Error())
+		}
+
+		fetched, err := client.PaymentService.Fetch(payment.ID)
+		if err != nil {
+			t.Fatalf("fetch payment returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: fetched.Type, expected: paymentType, name: "Type"},
+			{actual: fetched.Attributes, expected: payment.Attributes, name: "Attributes"},
+			{actual: fetched.Attributes.BeneficiaryParty.AccountName, expected: "Jane Doe", name: "BeneficiaryParty.AccountName"},
+		})
+	})
+
+	t.Run("When listing payments with filter then only matching payments are returned", func(t *testing.T) {
+		server := form3test.NewServer()
+		defer server.Close()
+
+		client := NewClient(nil, server.URL)
+		eur, err := givenPayment(client, "EUR", PaymentSchemeSEPAInstant)
+		if err != nil {
+			t.Fatalf("error while creating payment, %s", err.Error())
+		}
+		if _, err := givenPayment(client, "GBP", PaymentSchemeFPS); err != nil {
+			t.Fatalf("error while creating payment, %s", err.Error())
+		}
+
+		payments, hasNext, err := client.PaymentService.List(ListOptions{}, PaymentFilter{Currency: "EUR"})
+		if err != nil {
+			t.Fatalf("list payments returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: len(payments), expected: 1, name: "Length"},
+			{actual: payments[0].ID, expected: eur.ID, name: "ID"},
+			{actual: hasNext, expected: false, name: "HasNext"},
+		})
+	})
+
+	t.Run("When submitting payment then submission status can be tracked", func(t *testing.T) {
+		payment, err := givenPayment(client, "GBP", PaymentSchemeFPS)
+		if err != nil {
+			t.Fatalf("error while creating payment, %s", err.Error())
+		}
+
+		submissionID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		submission, err := client.PaymentService.CreateSubmission(payment.ID, submissionID)
+		if err != nil {
+			t.Fatalf("create submission returned with error %v", err.Error())
+		}
+
+		server.Put(resourcePath(transactionPaymentsBasePath, payment.ID, paymentSubmissionsPath), jsonapi.Resource{
+			ID:         submission.ID,
+			Type:       paymentSubmissionType,
+			Version:    1,
+			Attributes: json.RawMessage(`{"status":"delivery_confirmed"}`),
+		})
+
+		fetched, err := client.PaymentService.FetchSubmission(payment.ID, submissionID)
+		if err != nil {
+			t.Fatalf("fetch submission returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: submission.Type, expected: paymentSubmissionType, name: "Type"},
+			{actual: fetched.Attributes.Status, expected: SubmissionStatusDeliveryConfirmed, name: "Status"},
+		})
+	})
+	t.Run("When client is scoped to organisation then submission is created in it", func(t *testing.T) {
+		payment, err := givenPayment(client, "GBP", PaymentSchemeFPS)
+		if err != nil {
+			t.Fatalf("error while creating payment, %s", err.Error())
+		}
+
+		submissionID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		submission, err := client.ForOrganisation(payment.OrganisationID).PaymentService.CreateSubmission(payment.ID, submissionID)
+		if err != nil {
+			t.Fatalf("create submission returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: submission.OrganisationID, expected: payment.OrganisationID, name: "OrganisationID"},
+		})
+	})
+}
+
+func givenPayment(client *Client, currency string, scheme PaymentScheme) (Payment, error) {
+	id, orgID, err := generateIDs()
+	if err != nil {
+		return Payment{}, err
+	}
+
+	return client.PaymentService.Create(PaymentRequest{
+		ID:             id,
+		OrganisationID: orgID,
+		Attributes: PaymentAttributes{
+			Amount:        "100.21",
+			Currency:      currency,
+			PaymentScheme: scheme,
+			Reference:     "Invoice 42",
+			DebtorParty: &PaymentParty{
+				AccountName:   "John Doe",
+				AccountNumber: "71268996",
+				BankID:        "400302",
+				BankIDCode:    "GBDSC",
+			},
+			BeneficiaryParty: &PaymentParty{
+				AccountName:   "Jane Doe",
+				AccountNumber: "31926819",
+				BankID:        "403000",
+				BankIDCode:    "GBDSC",
+			},
+		},
+	})
+}
@@ -0,0 +1,147 @@
+package form3
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	reportingTransactionsBasePath = "/v1/reporting/transactions"
+	reportingPositionsBasePath    = "/v1/reporting/positions"
+	reportingReportsBasePath      = "/v1/reporting/reports"
+	reportContentPath             = "content"
+
+	reportDateLayout = "2006-01-02"
+)
+
+// DateRange limits reports to dates between From and To inclusive. Zero values are not used for filtering.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Transaction describes a booked movement of funds on an account.
+type Transaction struct {
+	ID             string                `json:"id,omitempty"`
+	OrganisationID string                `json:"organisation_id,omitempty"`
+	Type           string                `json:"type,omitempty"`
+	Attributes     TransactionAttributes `json:"attributes,omitempty"`
+}
+
+// TransactionAttributes describes attributes of a transaction.
+type TransactionAttributes struct {
+	AccountID string `json:"account_id,omitempty"`
+	// Amount is a decimal amount of the transaction, e.g. "100.21".
+	Amount   string `json:"amount,omitempty"`
+	Currency string `json:"currency,omitempty"`
+	// Direction is either "credit" or "debit".
+	Direction   string `json:"direction,omitempty"`
+	BookingDate string `json:"booking_date,omitempty"`
+	ValueDate   string `json:"value_date,omitempty"`
+	Reference   string `json:"reference,omitempty"`
+	PaymentID   string `json:"payment_id,omitempty"`
+}
+
+// Position describes a daily balance of an account.
+type Position struct {
+	ID             string             `json:"id,omitempty"`
+	OrganisationID string             `json:"organisation_id,omitempty"`
+	Type           string             `json:"type,omitempty"`
+	Attributes     PositionAttributes `json:"attributes,omitempty"`
+}
+
+// PositionAttributes describes attributes of a position.
+type PositionAttributes struct {
+	AccountID      string `json:"account_id,omitempty"`
+	Date           string `json:"date,omitempty"`
+	Currency       string `json:"currency,omitempty"`
+	OpeningBalance string `json:"opening_balance,omitempty"`
+	ClosingBalance string `json:"closing_balance,omitempty"`
+}
+
+// Report describes a generated report file.
+type Report struct {
+	ID             string           `json:"id,omitempty"`
+	OrganisationID string           `json:"organisation_id,omitempty"`
+	CreatedOn      string           `json:"created_on,omitempty"`
+	Type           string           `json:"type,omitempty"`
+	Attributes     ReportAttributes `json:"attributes,omitempty"`
+}
+
+// ReportAttributes describes attributes of a report file.
+type ReportAttributes struct {
+	Name        string `json:"name,omitempty"`
+	Date        string `json:"date,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+type ReportService struct {
+	client *Client
+}
+
+// ListTransactions lists transactions of an account with value dates within the date range. Accepts pagination options
+// as an argument. Returns list of transactions, true if there are more pages with transactions or an error for network
+// problem, and for non-2xx server statuses.
+func (r *ReportService) ListTransactions(accountID string, dateRange DateRange, options ListOptions) ([]Transaction, bool, error) {
+	query := pagingQuery(options)
+	setFilter(query, "account_id", accountID)
+	setDateRangeFilter(query, "value_date", dateRange)
+
+	var transactions []Transaction
+	hasNext, err := r.client.listResources(reportingTransactionsBasePath, query, &transactions)
+
+	return transactions, hasNext, err
+}
+
+// ListPositions lists daily positions of an account within the date range. Accepts pagination options as an argument.
+// Returns list of positions, true if there are more pages with positions or an error for network problem, and for
+// non-2xx server statuses.
+func (r *ReportService) ListPositions(accountID string, dateRange DateRange, options ListOptions) ([]Position, bool, error) {
+	query := pagingQuery(options)
+	setFilter(query, "account_id", accountID)
+	setDateRangeFilter(query, "date", dateRange)
+
+	var positions []Position
+	hasNext, err := r.client.listResources(reportingPositionsBasePath, query, &positions)
+
+	return positions, hasNext, err
+}
+
+// ListReports lists report files generated within the date range. Accepts pagination options as an argument. Returns
+// list of reports, true if there are more pages with reports or an error for network problem, and for non-2xx server
+// statuses.
+func (r *ReportService) ListReports(dateRange DateRange, options ListOptions) ([]Report, bool, error) {
+	query := pagingQuery(options)
+	setDateRangeFilter(query, "date", dateRange)
+
+	var reports []Report
+	hasNext, err := r.client.listResources(reportingReportsBasePath, query, &reports)
+
+	return reports, hasNext, err
+}
+
+// Download streams content of a report file. The content is not buffered, so large reports can be copied straight to
+// a file. The caller must close the returned reader. Returns an error for network problem, and for non-2xx server
+// statuses.
+func (r *ReportService) Download(reportID string) (io.ReadCloser, error) {
+	req, err := r.client.newRequest(http.MethodGet, &url.URL{Path: resourcePath(reportingReportsBasePath, reportID, reportContentPath)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "*/*")
+
+	return r.client.stream(req)
+}
+
+// setDateRangeFilter adds filter[name_from] and filter[name_to] query parameters for non-zero dates of the range.
+func setDateRangeFilter(query url.Values, name string, dateRange DateRange) {
+	if !dateRange.From.IsZero() {
+		setFilter(query, name+"_from", dateRange.From.Format(reportDateLayout))
+	}
+	if !dateRange.To.IsZero() {
+		setFilter(query, name+"_to", dateRange.To.Format(reportDateLayout))
+	}
+}
@@ -0,0 +1,98 @@
+package form3
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ptrsd/form3/form3test"
+	"github.com/ptrsd/form3/jsonapi"
+)
+
+func TestReportService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	for idx, date := range []string{"2020-05-01", "2020-05-02", "2020-05-03"} {
+		server.Put(reportingTransactionsBasePath, jsonapi.Resource{
+			ID:         fmt.Sprintf("t%d", idx),
+			Attributes: json.RawMessage(fmt.Sprintf(`{"account_id":"a1","amount":"10.00","value_date":%q}`, date)),
+		})
+		server.Put(reportingPositionsBasePath, jsonapi.Resource{
+			ID:         fmt.Sprintf("p%d", idx),
+			Attributes: json.RawMessage(fmt.Sprintf(`{"account_id":"a1","closing_balance":"%d0.00","date":%q}`, idx+1, date)),
+		})
+	}
+	server.Put(reportingTransactionsBasePath, jsonapi.Resource{
+		ID:         "other",
+		Attributes: json.RawMessage(`{"account_id":"a2","value_date":"2020-05-02"}`),
+	})
+
+	t.Run("When listing transactions within date range then only transactions of the account are returned", func(t *testing.T) {
+		dateRange := DateRange{
+			From: time.Date(2020, 5, 2, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC),
+		}
+
+		transactions, hasNext, err := client.ReportService.ListTransactions("a1", dateRange, ListOptions{PageSize: 1})
+		if err != nil {
+			t.Fatalf("list transactions returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: len(transactions), expected: 1, name: "Length"},
+			{actual: transactions[0].Attributes.ValueDate, expected: "2020-05-02", name: "ValueDate"},
+			{actual: hasNext, expected: true, name: "HasNext"},
+		})
+	})
+
+	t.Run("When listing positions from date then later positions are returned", func(t *testing.T) {
+		positions, _, err := client.ReportService.ListPositions("a1", DateRange{From: time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC)}, ListOptions{})
+		if err != nil {
+			t.Fatalf("list positions returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: len(positions), expected: 1, name: "Length"},
+			{actual: positions[0].Attributes.ClosingBalance, expected: "30.00", name: "ClosingBalance"},
+		})
+	})
+
+	t.Run("When downloading report then content is streamed", func(t *testing.T) {
+		server.HandleFunc(resourcePath(reportingReportsBasePath, "r1", reportContentPath), func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/csv")
+			fmt.Fprint(w, "account_id,amount\na1,10.00\n")
+		})
+
+		content, err := client.ReportService.Download("r1")
+		if err != nil {
+			t.Fatalf("download report returned with error %v", err.Error())
+		}
+		defer content.Close()
+
+		body, err := ioutil.ReadAll(content)
+		if err != nil {
+			t.Fatalf("error while reading report, %s", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: string(body), expected: "account_id,amount\na1,10.00\n", name: "Content"},
+		})
+	})
+
+	t.Run("When downloading not existing report then error", func(t *testing.T) {
+		reportID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		if _, err := client.ReportService.Download(reportID); err == nil {
+			t.Errorf("download of not existing report should return error")
+		}
+	})
+}
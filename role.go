@@ -0,0 +1,185 @@
+package form3
+
+const (
+	securityRolesBasePath = "/v1/security/roles"
+	acesPath              = "aces"
+	roleType              = "roles"
+	aceType               = "aces"
+)
+
+// ACEAction is an action allowed by an access control entry.
+type ACEAction string
+
+const (
+	ACEActionCreate         ACEAction = "CREATE"
+	ACEActionRead           ACEAction = "READ"
+	ACEActionEdit           ACEAction = "EDIT"
+	ACEActionDelete         ACEAction = "DELETE"
+	ACEActionCreateApproval ACEAction = "CREATE_APPROVAL"
+)
+
+// Role describes a named set of access control entries granted to users.
+type Role struct {
+	// ID is a mandatory, UUID version 4 field. It identifies role within a system.
+	ID             string         `json:"id,omitempty"`
+	OrganisationID string         `json:"organisation_id,omitempty"`
+	CreatedOn      string         `json:"created_on,omitempty"`
+	ModifiedOn     string         `json:"modified_on,omitempty"`
+	Type           string         `json:"type,omitempty"`
+	Version        int            `json:"version,omitempty"`
+	Attributes     RoleAttributes `json:"attributes,omitempty"`
+}
+
+// RoleAttributes describes attributes of a role.
+type RoleAttributes struct {
+	// Name is a mandatory name of the role.
+	Name         string `json:"name,omitempty"`
+	ParentRoleID string `json:"parent_role_id,omitempty"`
+}
+
+// RoleRequest describes a role to be created or updated.
+type RoleRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies role within a system.
+	ID             string `json:"id,omitempty"`
+	OrganisationID string `json:"organisation_id"`
+	Type           string `json:"type,omitempty"`
+	// Version is mandatory for updates and must match the current version of the role.
+	Version    int            `json:"version"`
+	Attributes RoleAttributes `json:"attributes"`
+}
+
+// ACE is an access control entry allowing an action on a record type to members of a role.
+type ACE struct {
+	// ID is a mandatory, UUID version 4 field. It identifies the entry within a system.
+	ID             string        `json:"id,omitempty"`
+	OrganisationID string        `json:"organisation_id,omitempty"`
+	CreatedOn      string        `json:"created_on,omitempty"`
+	ModifiedOn     string        `json:"modified_on,omitempty"`
+	Type           string        `json:"type,omitempty"`
+	Version        int           `json:"version,omitempty"`
+	Attributes     ACEAttributes `json:"attributes,omitempty"`
+}
+
+// ACEAttributes describes what is allowed by an access control entry.
+type ACEAttributes struct {
+	// RoleID is ID of the role the entry belongs to. It is set by the server.
+	RoleID string `json:"role_id,omitempty"`
+	// Action is a mandatory action allowed by the entry.
+	Action ACEAction `json:"action,omitempty"`
+	// RecordType is a mandatory type of records the action is allowed on, e.g. RecordTypeAccounts.
+	RecordType string `json:"record_type,omitempty"`
+	// Filter optionally narrows down records the action is allowed on.
+	Filter string `json:"filter,omitempty"`
+}
+
+// ACERequest describes an access control entry to be created.
+type ACERequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies the entry within a system.
+	ID             string        `json:"id,omitempty"`
+	OrganisationID string        `json:"organisation_id"`
+	Type           string        `json:"type,omitempty"`
+	Attributes     ACEAttributes `json:"attributes"`
+}
+
+type RoleService struct {
+	client *Client
+}
+
+// Create a new role. Returns Role or an error for network problem, and for non-2xx server statuses.
+func (r *RoleService) Create(createReq RoleRequest) (Role, error) {
+	if createReq.Type == "" {
+		createReq.Type = roleType
+	}
+	if createReq.OrganisationID == "" {
+		createReq.OrganisationID = r.client.OrganisationID
+	}
+
+	role := Role{}
+	err := r.client.createResource(securityRolesBasePath, createReq, &role)
+
+	return role, err
+}
+
+// Fetch a Role based on ID. Returns a role or an error for network problem, and for non-2xx server statuses.
+func (r *RoleService) Fetch(id string) (Role, error) {
+	role := Role{}
+	err := r.client.fetchResource(resourcePath(securityRolesBasePath, id), &role)
+
+	return role, err
+}
+
+// List roles of Client.OrganisationID, or all roles if it is empty. Accepts pagination options as an argument. Returns
+// list of roles, true if there are more pages with roles or an error for network problem, and for non-2xx server
+// statuses.
+func (r *RoleService) List(options ListOptions) ([]Role, bool, error) {
+	var roles []Role
+	query := pagingQuery(options)
+	setFilter(query, "organisation_id", r.client.OrganisationID)
+
+	hasNext, err := r.client.listResources(securityRolesBasePath, query, &roles)
+
+	return roles, hasNext, err
+}
+
+// Update attributes of a role. Only non-empty attributes are changed. The request version must match the current
+// version of the role. Returns the updated role or an error for network problem, and for non-2xx server statuses.
+func (r *RoleService) Update(updateReq RoleRequest) (Role, error) {
+	if updateReq.Type == "" {
+		updateReq.Type = roleType
+	}
+
+	role := Role{}
+	err := r.client.updateResource(resourcePath(securityRolesBasePath, updateReq.ID), updateReq, &role)
+
+	return role, err
+}
+
+// Delete a role. Returns error for network problem, and for non-2xx server statuses.
+func (r *RoleService) Delete(id string, version int) error {
+	return r.client.deleteResource(resourcePath(securityRolesBasePath, id), version)
+}
+
+// ACEService manages access control entries. Entries are sub-resources of a role.
+type ACEService struct {
+	client *Client
+}
+
+// Create a new access control entry of the role. Requests without an organisation ID are created in
+// Client.OrganisationID. Returns ACE or an error for network problem, and for non-2xx server statuses.
+func (a *ACEService) Create(roleID string, createReq ACERequest) (ACE, error) {
+	if createReq.Type == "" {
+		createReq.Type = aceType
+	}
+	if createReq.OrganisationID == "" {
+		createReq.OrganisationID = a.client.OrganisationID
+	}
+	createReq.Attributes.RoleID = roleID
+
+	ace := ACE{}
+	err := a.client.createResource(resourcePath(securityRolesBasePath, roleID, acesPath), createReq, &ace)
+
+	return ace, err
+}
+
+// Fetch an access control entry of the role. Returns ACE or an error for network problem, and for non-2xx server
+// statuses.
+func (a *ACEService) Fetch(roleID, id string) (ACE, error) {
+	ace := ACE{}
+	err := a.client.fetchResource(resourcePath(securityRolesBasePath, roleID, acesPath, id), &ace)
+
+	return ace, err
+}
+
+// List access control entries of the role. Accepts pagination options as an argument. Returns list of entries, true if
+// there are more pages with entries or an error for network problem, and for non-2xx server statuses.
+func (a *ACEService) List(roleID string, options ListOptions) ([]ACE, bool, error) {
+	var aces []ACE
+	hasNext, err := a.client.listResources(resourcePath(securityRolesBasePath, roleID, acesPath), pagingQuery(options), &aces)
+
+	return aces, hasNext, err
+}
+
+// Delete an access control entry of the role. Returns error for network problem, and for non-2xx server statuses.
+func (a *ACEService) Delete(roleID, id string, version int) error {
+	return a.client.deleteResource(resourcePath(securityRolesBasePath, roleID, acesPath, id), version)
+}
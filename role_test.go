@@ -0,0 +1,158 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestRoleService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	t.Run("When creating role then it can be fetched", func(t *testing.T) {
+		role, err := givenRole(client, "Account managers")
+		if err != nil {
+			t.Fatalf("create role returned with error %v", err.Error())
+		}
+
+		fetched, err := client.RoleService.Fetch(role.ID)
+		if err != nil {
+			t.Fatalf("fetch role returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: fetched, expected: role, name: "Role"},
+		})
+	})
+	t.Run("When updating role then changed attributes are updated", func(t *testing.T) {
+		role, err := givenRole(client, "Auditors")
+		if err != nil {
+			t.Fatalf("create role returned with error %v", err.Error())
+		}
+
+		updated, err := client.RoleService.Update(RoleRequest{
+			ID:         role.ID,
+			Version:    role.Version,
+			Attributes: RoleAttributes{Name: "External auditors"},
+		})
+		if err != nil {
+			t.Fatalf("update role returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: updated.Attributes.Name, expected: "External auditors", name: "Name"},
+			{actual: updated.Version, expected: role.Version + 1, name: "Version"},
+		})
+	})
+
+	t.Run("When client is scoped to organisation then only its roles are listed", func(t *testing.T) {
+		role, err := givenRole(client, "Payment approvers")
+		if err != nil {
+			t.Fatalf("create role returned with error %v", err.Error())
+		}
+
+		roles, _, err := client.ForOrganisation(role.OrganisationID).RoleService.List(ListOptions{})
+		if err != nil {
+			t.Fatalf("list roles returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: roles, expected: []Role{role}, name: "Roles"},
+		})
+	})
+}
+
+func TestACEService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	role, err := givenRole(client, "Readers")
+	if err != nil {
+		t.Fatalf("create role returned with error %v", err.Error())
+	}
+
+	t.Run("When creating entries then they are listed under the role", func(t *testing.T) {
+		for _, action := range []ACEAction{ACEActionRead, ACEActionCreate} {
+			id, err := generateRandomUUID()
+			if err != nil {
+				t.Fatalf("error while generating random uuid, %s", err.Error())
+			}
+
+			_, err = client.ACEService.Create(role.ID, ACERequest{
+				ID:             id,
+				OrganisationID: role.OrganisationID,
+				Attributes:     ACEAttributes{Action: action, RecordType: RecordTypeAccounts},
+			})
+			if err != nil {
+				t.Fatalf("create ace returned with error %v", err.Error())
+			}
+		}
+
+		aces, _, err := client.ACEService.List(role.ID, ListOptions{})
+		if err != nil {
+			t.Fatalf("list aces returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: len(aces), expected: 2, name: "Length"},
+			{actual: aces[0].Attributes.RoleID, expected: role.ID, name: "RoleID"},
+			{actual: aces[1].Attributes.Action, expected: ACEActionCreate, name: "Action"},
+		})
+
+		if err := client.ACEService.Delete(role.ID, aces[0].ID, aces[0].Version); err != nil {
+			t.Fatalf("delete ace returned with error %v", err.Error())
+		}
+
+		if _, err := client.ACEService.Fetch(role.ID, aces[0].ID); err == nil {
+			t.Errorf("fetch of deleted ace should return error")
+		}
+	})
+
+	t.Run("When client is scoped to organisation then entry is created in it", func(t *testing.T) {
+		id, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		ace, err := client.ForOrganisation(role.OrganisationID).ACEService.Create(role.ID, ACERequest{
+			ID:         id,
+			Attributes: ACEAttributes{Action: ACEActionRead, RecordType: RecordTypeAccounts},
+		})
+		if err != nil {
+			t.Fatalf("create ace returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: ace.OrganisationID, expected: role.OrganisationID, name: "OrganisationID"},
+		})
+	})
+
+	t.Run("When listing entries of not existing role then error", func(t *testing.T) {
+		roleID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		if _, _, err := client.ACEService.List(roleID, ListOptions{}); err == nil {
+			t.Errorf("list of entries of not existing role should return error")
+		}
+	})
+}
+
+func givenRole(client *Client, name string) (Role, error) {
+	id, orgID, err := generateIDs()
+	if err != nil {
+		return Role{}, err
+	}
+
+	return client.RoleService.Create(RoleRequest{
+		ID:             id,
+		OrganisationID: orgID,
+		Attributes:     RoleAttributes{Name: name},
+	})
+}
@@ -0,0 +1,140 @@
+package form3
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signer signs requests before they are sent, e.g. with a key registered for an API user.
+type Signer interface {
+	Sign(req *http.Request) error
+}
+
+// RSASigner signs requests with HTTP message signatures using rsa-sha256, the scheme of Form3 API keys. Requests get
+// Date, Digest for bodies, and an Authorization header carrying the signature, which replaces the bearer token.
+type RSASigner struct {
+	// KeyID is the ID of the public key registered in Form3.
+	KeyID string
+	Key   *rsa.PrivateKey
+	// Now returns the time of the Date header, time.Now if nil.
+	Now func() time.Time
+}
+
+// LoadRSASigner reads a PEM encoded PKCS #1 or PKCS #8 RSA private key from the file.
+func LoadRSASigner(keyID, path string) (*RSASigner, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM encoded key", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return &RSASigner{KeyID: keyID, Key: key}, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing key %s, %w", path, err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an RSA key", path)
+	}
+
+	return &RSASigner{KeyID: keyID, Key: key}, nil
+}
+
+// Sign adds the signature to the request.
+func (s *RSASigner) Sign(req *http.Request) error {
+	if s.Key == nil {
+		return errors.New("signing key is not set")
+	}
+
+	if req.Header.Get("Date") == "" {
+		now := time.Now
+		if s.Now != nil {
+			now = s.Now
+		}
+		req.Header.Set("Date", now().UTC().Format(http.TimeFormat))
+	}
+
+	headers := []string{"(request-target)", "host", "date", "accept"}
+
+	body, err := requestBody(req)
+	if err != nil {
+		return err
+	}
+	if len(body) > 0 {
+		digest := sha256.Sum256(body)
+		req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+		headers = append(headers, "digest", "content-type", "content-length")
+	}
+
+	hashed := sha256.Sum256([]byte(SigningString(req, headers)))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.Key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(`Signature keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		s.KeyID, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+
+	return nil
+}
+
+// SigningString returns the string signed for the request headers, as described by the HTTP message signatures draft.
+func SigningString(req *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers))
+	for _, header := range headers {
+		var value string
+		switch header {
+		case "(request-target)":
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		case "content-length":
+			value = strconv.FormatInt(req.ContentLength, 10)
+		default:
+			value = req.Header.Get(header)
+		}
+
+		lines = append(lines, header+": "+value)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// requestBody returns the body of the request without consuming it.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return nil, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return ioutil.ReadAll(body)
+}
@@ -0,0 +1,135 @@
+package form3
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestRSASigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error while generating key, %s", err.Error())
+	}
+
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatalf("error while creating directory, %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "key.pem")
+	encoded := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(path, encoded, 0600); err != nil {
+		t.Fatalf("error while writing key, %s", err.Error())
+	}
+
+	signer, err := LoadRSASigner("75a8ba12-fff2-4a52-ad8a-e8b34c5ccec8", path)
+	if err != nil {
+		t.Fatalf("error while loading key, %s", err.Error())
+	}
+	signer.Now = func() time.Time { return time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC) }
+
+	client := testClient("http://api.form3.tech")
+	client.Token = "secret"
+	client.Signer = signer
+
+	t.Run("When request has a body then it is signed with its digest", func(t *testing.T) {
+		req, err := client.newRequest(http.MethodPost, &url.URL{Path: "/v1/organisation/accounts"}, map[string]string{"id": "1"})
+		if err != nil {
+			t.Fatalf("error while creating new request, %s", err.Error())
+		}
+		if err := client.Signer.Sign(req); err != nil {
+			t.Fatalf("error while signing request, %s", err.Error())
+		}
+
+		digest := sha256.Sum256([]byte("{\"id\":\"1\"}\n"))
+		authorization := req.Header.Get("Authorization")
+
+		thenEquals(t, assertions{
+			{actual: req.Header.Get("Date"), expected: "Wed, 01 Jan 2020 10:00:00 GMT", name: "Date"},
+			{actual: req.Header.Get("Digest"), expected: "SHA-256=" + base64.StdEncoding.EncodeToString(digest[:]), name: "Digest"},
+			{actual: strings.HasPrefix(authorization, `Signature keyId="75a8ba12-fff2-4a52-ad8a-e8b34c5ccec8",algorithm="rsa-sha256",`), expected: true, name: "Authorization"},
+		})
+		thenSignatureIsValid(t, req, &key.PublicKey)
+	})
+
+	t.Run("When request has no body then it is signed without digest", func(t *testing.T) {
+		req, err := client.newRequest(http.MethodGet, &url.URL{Path: "/v1/organisation/accounts", RawQuery: "page[number]=1"}, nil)
+		if err != nil {
+			t.Fatalf("error while creating new request, %s", err.Error())
+		}
+		if err := client.Signer.Sign(req); err != nil {
+			t.Fatalf("error while signing request, %s", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: req.Header.Get("Digest"), expected: "", name: "Digest"},
+			{actual: strings.Contains(req.Header.Get("Authorization"), `headers="(request-target) host date accept"`), expected: true, name: "Headers"},
+		})
+		thenSignatureIsValid(t, req, &key.PublicKey)
+	})
+
+	t.Run("When downloading report then accept header is signed as sent", func(t *testing.T) {
+		server := form3test.NewServer()
+		defer server.Close()
+
+		var received *http.Request
+		server.HandleFunc(resourcePath(reportingReportsBasePath, "r1", reportContentPath), func(w http.ResponseWriter, r *http.Request) {
+			received = r.Clone(r.Context())
+			fmt.Fprint(w, "account_id,amount\n")
+		})
+
+		download := NewClient(nil, server.URL)
+		download.Signer = signer
+
+		content, err := download.ReportService.Download("r1")
+		if err != nil {
+			t.Fatalf("download report returned with error %v", err.Error())
+		}
+		content.Close()
+
+		thenEquals(t, assertions{
+			{actual: received.Header.Get("Accept"), expected: "*/*", name: "Accept"},
+		})
+		thenSignatureIsValid(t, received, &key.PublicKey)
+	})
+
+	t.Run("When key file does not exist then error is returned", func(t *testing.T) {
+		_, err := LoadRSASigner("key", filepath.Join(dir, "missing.pem"))
+		thenEquals(t, assertions{
+			{actual: err != nil, expected: true, name: "Error"},
+		})
+	})
+}
+
+func thenSignatureIsValid(t *testing.T, req *http.Request, key *rsa.PublicKey) {
+	t.Helper()
+
+	matches := regexp.MustCompile(`headers="([^"]*)",signature="([^"]*)"`).FindStringSubmatch(req.Header.Get("Authorization"))
+	if matches == nil {
+		t.Fatalf("authorization header %q is not a signature", req.Header.Get("Authorization"))
+	}
+
+	signature, _ := base64.StdEncoding.DecodeString(matches[2])
+	hashed := sha256.Sum256([]byte(SigningString(req, strings.Split(matches[1], " "))))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature); err != nil {
+		t.Errorf("signature is invalid, %s", err.Error())
+	}
+}
@@ -0,0 +1,103 @@
+package form3
+
+const (
+	notificationSubscriptionsBasePath = "/v1/notification/subscriptions"
+	subscriptionType                  = "subscriptions"
+)
+
+// Record types which changes can be subscribed to.
+const (
+	RecordTypeAccounts           = typ
+	RecordTypePayments           = paymentType
+	RecordTypePaymentSubmissions = paymentSubmissionType
+)
+
+// Event types which can be subscribed to.
+const (
+	EventTypeCreated = "created"
+	EventTypeUpdated = "updated"
+	EventTypeDeleted = "deleted"
+)
+
+// Subscription describes a registered callback for changes of records.
+type Subscription struct {
+	// ID is a mandatory, UUID version 4 field. It identifies subscription within a system.
+	ID string `json:"id,omitempty"`
+	// OrganisationID is a mandatory UUID version 4 field. Notifications are sent for records of the organisation.
+	OrganisationID string                 `json:"organisation_id,omitempty"`
+	CreatedOn      string                 `json:"created_on,omitempty"`
+	ModifiedOn     string                 `json:"modified_on,omitempty"`
+	Type           string                 `json:"type,omitempty"`
+	Version        int                    `json:"version,omitempty"`
+	Attributes     SubscriptionAttributes `json:"attributes,omitempty"`
+}
+
+// SubscriptionAttributes describes where and for which changes notifications are sent.
+type SubscriptionAttributes struct {
+	// CallbackURI is a mandatory URL of the notification receiver.
+	CallbackURI string `json:"callback_uri,omitempty"`
+	// CallbackTransport is a transport used to deliver notifications. Default: http.
+	CallbackTransport string `json:"callback_transport,omitempty"`
+	// RecordType is a mandatory type of records, e.g. RecordTypeAccounts.
+	RecordType string `json:"record_type,omitempty"`
+	// EventType is a mandatory type of changes, e.g. EventTypeCreated.
+	EventType   string `json:"event_type,omitempty"`
+	Deactivated bool   `json:"deactivated,omitempty"`
+}
+
+// SubscriptionRequest describes a subscription to be created.
+type SubscriptionRequest struct {
+	ID             string                 `json:"id,omitempty"`
+	OrganisationID string                 `json:"organisation_id"`
+	Type           string                 `json:"type,omitempty"`
+	Attributes     SubscriptionAttributes `json:"attributes"`
+}
+
+type SubscriptionService struct {
+	client *Client
+}
+
+// Create a new subscription. Returns Subscription or an error for network problem, and for non-2xx server statuses.
+func (s *SubscriptionService) Create(createReq SubscriptionRequest) (Subscription, error) {
+	if createReq.Type == "" {
+		createReq.Type = subscriptionType
+	}
+	if createReq.OrganisationID == "" {
+		createReq.OrganisationID = s.client.OrganisationID
+	}
+	if createReq.Attributes.CallbackTransport == "" {
+		createReq.Attributes.CallbackTransport = "http"
+	}
+
+	subscription := Subscription{}
+	err := s.client.createResource(notificationSubscriptionsBasePath, createReq, &subscription)
+
+	return subscription, err
+}
+
+// Fetch a Subscription based on ID. Returns a subscription or an error for network problem, and for non-2xx server
+// statuses.
+func (s *SubscriptionService) Fetch(id string) (Subscription, error) {
+	subscription := Subscription{}
+	err := s.client.fetchResource(resourcePath(notificationSubscriptionsBasePath, id), &subscription)
+
+	return subscription, err
+}
+
+// List subscriptions of Client.OrganisationID, or all subscriptions if it is empty. Accepts pagination options as an
+// argument. Returns list of subscriptions, true if there are more pages with subscriptions or an error for network
+// problem, and for non-2xx server statuses.
+func (s *SubscriptionService) List(options ListOptions) ([]Subscription, bool, error) {
+	var subscriptions []Subscription
+	query := pagingQuery(options)
+	setFilter(query, "organisation_id", s.client.OrganisationID)
+
+	hasNext, err := s.client.listResources(notificationSubscriptionsBasePath, query, &subscriptions)
+
+	return subscriptions, hasNext, err
+}
+
+// Delete a subscription. Returns error for network problem, and for non-2xx server statuses.
+func (s *SubscriptionService) Delete(id string, version int) error {
+	return s.client.deleteResource(resourcePath(notificationSubscriptionsBasePath, id), version)
+}
@@ -0,0 +1,75 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestSubscriptionService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	t.Run("When creating subscription then it is listed and can be deleted", func(t *testing.T) {
+		subscription, err := givenSubscription(client)
+		if err != nil {
+			t.Fatalf("create subscription returned with error %v", err.Error())
+		}
+
+		subscriptions, _, err := client.SubscriptionService.List(ListOptions{})
+		if err != nil {
+			t.Fatalf("list subscriptions returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: subscription.Type, expected: subscriptionType, name: "Type"},
+			{actual: subscription.Attributes.CallbackTransport, expected: "http", name: "CallbackTransport"},
+			{actual: subscriptions, expected: []Subscription{subscription}, name: "Subscriptions"},
+		})
+
+		if err := client.SubscriptionService.Delete(subscription.ID, subscription.Version); err != nil {
+			t.Fatalf("delete subscription returned with error %v", err.Error())
+		}
+
+		if _, err := client.SubscriptionService.Fetch(subscription.ID); err == nil {
+			t.Errorf("fetch of deleted subscription should return error")
+		}
+	})
+	t.Run("When client is scoped to organisation then only its subscriptions are listed", func(t *testing.T) {
+		subscription, err := givenSubscription(client)
+		if err != nil {
+			t.Fatalf("create subscription returned with error %v", err.Error())
+		}
+		if _, err := givenSubscription(client); err != nil {
+			t.Fatalf("create subscription returned with error %v", err.Error())
+		}
+
+		subscriptions, _, err := client.ForOrganisation(subscription.OrganisationID).SubscriptionService.List(ListOptions{})
+		if err != nil {
+			t.Fatalf("list subscriptions returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: subscriptions, expected: []Subscription{subscription}, name: "Subscriptions"},
+		})
+	})
+}
+
+func givenSubscription(client *Client) (Subscription, error) {
+	id, orgID, err := generateIDs()
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return client.SubscriptionService.Create(SubscriptionRequest{
+		ID:             id,
+		OrganisationID: orgID,
+		Attributes: SubscriptionAttributes{
+			CallbackURI: "https://example.com/notifications",
+			RecordType:  RecordTypeAccounts,
+			EventType:   EventTypeCreated,
+		},
+	})
+}
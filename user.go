@@ -0,0 +1,119 @@
+package form3
+
+const (
+	securityUsersBasePath = "/v1/security/users"
+	userType              = "users"
+)
+
+// User describes an API user.
+type User struct {
+	// ID is a mandatory, UUID version 4 field. It identifies user within a system.
+	ID string `json:"id,omitempty"`
+	// OrganisationID is a mandatory UUID version 4 field. It identifies organisation the user belongs to.
+	OrganisationID string         `json:"organisation_id,omitempty"`
+	CreatedOn      string         `json:"created_on,omitempty"`
+	ModifiedOn     string         `json:"modified_on,omitempty"`
+	Type           string         `json:"type,omitempty"`
+	Version        int            `json:"version,omitempty"`
+	Attributes     UserAttributes `json:"attributes,omitempty"`
+}
+
+// UserAttributes describes attributes of a user.
+type UserAttributes struct {
+	// Username is a mandatory, unique name of the user.
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	// RoleIDs are IDs of roles granted to the user. Updates replace the roles unless RoleIDs is nil, so an empty
+	// slice revokes all roles.
+	RoleIDs []string `json:"role_ids,omitempty"`
+}
+
+// userUpdate is an update of a user which sends role_ids whenever they are not nil, including an empty list.
+type userUpdate struct {
+	UserRequest
+	Attributes userUpdateAttributes `json:"attributes"`
+}
+
+type userUpdateAttributes struct {
+	Username string    `json:"username,omitempty"`
+	Email    string    `json:"email,omitempty"`
+	RoleIDs  *[]string `json:"role_ids,omitempty"`
+}
+
+// UserRequest describes a user to be created or updated.
+type UserRequest struct {
+	// ID is a mandatory, UUID version 4 field. It identifies user within a system.
+	ID             string `json:"id,omitempty"`
+	OrganisationID string `json:"organisation_id"`
+	Type           string `json:"type,omitempty"`
+	// Version is mandatory for updates and must match the current version of the user.
+	Version    int            `json:"version"`
+	Attributes UserAttributes `json:"attributes"`
+}
+
+type UserService struct {
+	client *Client
+}
+
+// Create a new user. Returns User or an error for network problem, and for non-2xx server statuses.
+func (u *UserService) Create(createReq UserRequest) (User, error) {
+	if createReq.Type == "" {
+		createReq.Type = userType
+	}
+	if createReq.OrganisationID == "" {
+		createReq.OrganisationID = u.client.OrganisationID
+	}
+
+	user := User{}
+	err := u.client.createResource(securityUsersBasePath, createReq, &user)
+
+	return user, err
+}
+
+// Fetch a User based on ID. Returns a user or an error for network problem, and for non-2xx server statuses.
+func (u *UserService) Fetch(id string) (User, error) {
+	user := User{}
+	err := u.client.fetchResource(resourcePath(securityUsersBasePath, id), &user)
+
+	return user, err
+}
+
+// List users of Client.OrganisationID, or all users if it is empty. Accepts pagination options as an argument. Returns
+// list of users, true if there are more pages with users or an error for network problem, and for non-2xx server
+// statuses.
+func (u *UserService) List(options ListOptions) ([]User, bool, error) {
+	var users []User
+	query := pagingQuery(options)
+	setFilter(query, "organisation_id", u.client.OrganisationID)
+
+	hasNext, err := u.client.listResources(securityUsersBasePath, query, &users)
+
+	return users, hasNext, err
+}
+
+// Update attributes of a user, e.g. roles granted to the user. Only non-empty attributes and non-nil roles are
+// changed. The request version must match the current version of the user. Returns the updated user or an error for
+// network problem, and for non-2xx server statuses.
+func (u *UserService) Update(updateReq UserRequest) (User, error) {
+	if updateReq.Type == "" {
+		updateReq.Type = userType
+	}
+
+	update := userUpdate{
+		UserRequest: updateReq,
+		Attributes:  userUpdateAttributes{Username: updateReq.Attributes.Username, Email: updateReq.Attributes.Email},
+	}
+	if updateReq.Attributes.RoleIDs != nil {
+		update.Attributes.RoleIDs = &updateReq.Attributes.RoleIDs
+	}
+
+	user := User{}
+	err := u.client.updateResource(resourcePath(securityUsersBasePath, updateReq.ID), update, &user)
+
+	return user, err
+}
+
+// Delete a user. Returns error for network problem, and for non-2xx server statuses.
+func (u *UserService) Delete(id string, version int) error {
+	return u.client.deleteResource(resourcePath(securityUsersBasePath, id), version)
+}
@@ -0,0 +1,147 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/ptrsd/form3/form3test"
+)
+
+func TestUserService(t *testing.T) {
+	server := form3test.NewServer()
+	defer server.Close()
+
+	client := NewClient(nil, server.URL)
+
+	t.Run("When creating user then it is listed and can be deleted", func(t *testing.T) {
+		user, err := givenUser(client, "team-payments")
+		if err != nil {
+			t.Fatalf("create user returned with error %v", err.Error())
+		}
+
+		users, hasNext, err := client.UserService.List(ListOptions{})
+		if err != nil {
+			t.Fatalf("list users returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: users, expected: []User{user}, name: "Users"},
+			{actual: hasNext, expected: false, name: "HasNext"},
+			{actual: user.Type, expected: userType, name: "Type"},
+		})
+
+		if err := client.UserService.Delete(user.ID, user.Version); err != nil {
+			t.Fatalf("delete user returned with error %v", err.Error())
+		}
+
+		if _, err := client.UserService.Fetch(user.ID); err == nil {
+			t.Errorf("fetch of deleted user should return error")
+		}
+	})
+	t.Run("When updating roles of user then they are replaced", func(t *testing.T) {
+		user, err := givenUser(client, "team-risk")
+		if err != nil {
+			t.Fatalf("create user returned with error %v", err.Error())
+		}
+
+		roleID, err := generateRandomUUID()
+		if err != nil {
+			t.Fatalf("error while generating random uuid, %s", err.Error())
+		}
+
+		updated, err := client.UserService.Update(UserRequest{
+			ID:         user.ID,
+			Version:    user.Version,
+			Attributes: UserAttributes{RoleIDs: []string{roleID}},
+		})
+		if err != nil {
+			t.Fatalf("update user returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: updated.Attributes.RoleIDs, expected: []string{roleID}, name: "RoleIDs"},
+			{actual: updated.Attributes.Username, expected: user.Attributes.Username, name: "Username"},
+			{actual: updated.Version, expected: user.Version + 1, name: "Version"},
+		})
+
+		_, err = client.UserService.Update(UserRequest{
+			ID:         user.ID,
+			Version:    user.Version,
+			Attributes: UserAttributes{Email: "risk@example.com"},
+		})
+		thenEquals(t, assertions{
+			{actual: IsConflict(err), expected: true, name: "StaleVersion.IsConflict"},
+		})
+	})
+
+	t.Run("When updating user with no roles then all roles are revoked", func(t *testing.T) {
+		user, err := givenUser(client, "team-leavers")
+		if err != nil {
+			t.Fatalf("create user returned with error %v", err.Error())
+		}
+
+		granted, err := client.UserService.Update(UserRequest{
+			ID:         user.ID,
+			Version:    user.Version,
+			Attributes: UserAttributes{RoleIDs: []string{"2ad1d3a4-8b2c-4a4f-9d5e-3f1b2c3d4e5f"}},
+		})
+		if err != nil {
+			t.Fatalf("update user returned with error %v", err.Error())
+		}
+
+		renamed, err := client.UserService.Update(UserRequest{
+			ID:         user.ID,
+			Version:    granted.Version,
+			Attributes: UserAttributes{Email: "leavers@example.com"},
+		})
+		if err != nil {
+			t.Fatalf("update user returned with error %v", err.Error())
+		}
+
+		revoked, err := client.UserService.Update(UserRequest{
+			ID:         user.ID,
+			Version:    renamed.Version,
+			Attributes: UserAttributes{RoleIDs: []string{}},
+		})
+		if err != nil {
+			t.Fatalf("update user returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: renamed.Attributes.RoleIDs, expected: granted.Attributes.RoleIDs, name: "Renamed.RoleIDs"},
+			{actual: len(revoked.Attributes.RoleIDs), expected: 0, name: "Revoked.RoleIDs"},
+			{actual: revoked.Attributes.Email, expected: "leavers@example.com", name: "Revoked.Email"},
+		})
+	})
+
+	t.Run("When client is scoped to organisation then only its users are listed", func(t *testing.T) {
+		user, err := givenUser(client, "team-cards")
+		if err != nil {
+			t.Fatalf("create user returned with error %v", err.Error())
+		}
+		if _, err := givenUser(client, "team-treasury"); err != nil {
+			t.Fatalf("create user returned with error %v", err.Error())
+		}
+
+		users, _, err := client.ForOrganisation(user.OrganisationID).UserService.List(ListOptions{})
+		if err != nil {
+			t.Fatalf("list users returned with error %v", err.Error())
+		}
+
+		thenEquals(t, assertions{
+			{actual: users, expected: []User{user}, name: "Users"},
+		})
+	})
+}
+
+func givenUser(client *Client, username string) (User, error) {
+	id, orgID, err := generateIDs()
+	if err != nil {
+		return User{}, err
+	}
+
+	return client.UserService.Create(UserRequest{
+		ID:             id,
+		OrganisationID: orgID,
+		Attributes:     UserAttributes{Username: username, Email: username + "@example.com"},
+	})
+}
@@ -0,0 +1,128 @@
+package form3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultCredentials reads a token from a KV secrets engine of HashiCorp Vault over its HTTP API. Tokens expire with the
+// lease of the secret, wrap the provider in CachedCredentials to read them once per lease.
+type VaultCredentials struct {
+	// Address of Vault, VAULT_ADDR if empty.
+	Address string
+	// Token authenticates to Vault, VAULT_TOKEN if empty.
+	Token string
+	// Path of the secret including the mount, e.g. secret/form3. For version 2 engines data/ is added after the mount.
+	Path string
+	// Field of the secret holding the token, token if empty.
+	Field string
+	// KVVersion is the version of the KV secrets engine, 1 if not set.
+	KVVersion int
+	// HTTPClient sends requests to Vault, http.DefaultClient if nil.
+	HTTPClient *http.Client
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// vaultSecret is a response of Vault to a read of a secret.
+type vaultSecret struct {
+	LeaseDuration int             `json:"lease_duration"`
+	Data          json.RawMessage `json:"data"`
+	Errors        []string        `json:"errors"`
+}
+
+// Credentials reads the secret and returns the token of its field, or an error for network problem, non-2xx Vault
+// statuses, and secrets without the field.
+func (v *VaultCredentials) Credentials() (Credentials, error) {
+	req, err := http.NewRequest(http.MethodGet, v.url(), nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("X-Vault-Token", valueOrEnv(v.Token, "VAULT_TOKEN"))
+
+	httpClient := v.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Credentials{}, err
+	}
+	defer resp.Body.Close()
+
+	secret := vaultSecret{}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&secret)
+
+	if resp.StatusCode != http.StatusOK {
+		if len(secret.Errors) > 0 {
+			return Credentials{}, fmt.Errorf("vault: %s", strings.Join(secret.Errors, ", "))
+		}
+		return Credentials{}, fmt.Errorf("vault: %s", resp.Status)
+	}
+	if decodeErr != nil {
+		return Credentials{}, fmt.Errorf("vault: %w", decodeErr)
+	}
+
+	data := secret.Data
+	if v.KVVersion == 2 {
+		versioned := struct {
+			Data json.RawMessage `json:"data"`
+		}{}
+		if err := json.Unmarshal(data, &versioned); err != nil {
+			return Credentials{}, fmt.Errorf("vault: %w", err)
+		}
+		data = versioned.Data
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Credentials{}, fmt.Errorf("vault: %w", err)
+	}
+
+	field := v.Field
+	if field == "" {
+		field = "token"
+	}
+
+	token, ok := fields[field].(string)
+	if !ok || token == "" {
+		return Credentials{}, fmt.Errorf("vault: secret %s has no %s", v.Path, field)
+	}
+
+	credentials := Credentials{Token: token}
+	if secret.LeaseDuration > 0 {
+		now := time.Now
+		if v.Now != nil {
+			now = v.Now
+		}
+		credentials.ExpiresAt = now().Add(time.Duration(secret.LeaseDuration) * time.Second)
+	}
+
+	return credentials, nil
+}
+
+func (v *VaultCredentials) url() string {
+	path := strings.Trim(v.Path, "/")
+	if v.KVVersion == 2 {
+		segments := strings.SplitN(path, "/", 2)
+		path = segments[0] + "/data"
+		if len(segments) > 1 {
+			path += "/" + segments[1]
+		}
+	}
+
+	return strings.TrimSuffix(valueOrEnv(v.Address, "VAULT_ADDR"), "/") + "/v1/" + path
+}
+
+func valueOrEnv(value, key string) string {
+	if value != "" {
+		return value
+	}
+
+	return os.Getenv(key)
+}
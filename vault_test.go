@@ -0,0 +1,97 @@
+package form3
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestVaultCredentials(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprintln(w, `{"errors":["permission denied"]}`)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/form3":
+			fmt.Fprintln(w, `{"lease_duration":60,"data":{"token":"secret","api_key":"key"}}`)
+		case "/v1/secret/data/form3":
+			fmt.Fprintln(w, `{"lease_duration":0,"data":{"data":{"token":"versioned"},"metadata":{"version":3}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"errors":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("When secret is read from KV version 1 then its lease sets expiry", func(t *testing.T) {
+		credentials, err := (&VaultCredentials{Address: server.URL, Token: "root", Path: "secret/form3", Now: func() time.Time { return now }}).Credentials()
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: credentials, expected: Credentials{Token: "secret", ExpiresAt: now.Add(time.Minute)}, name: "Credentials"},
+		})
+	})
+
+	t.Run("When secret is read from KV version 2 then versioned data is used", func(t *testing.T) {
+		credentials, err := (&VaultCredentials{Address: server.URL, Token: "root", Path: "/secret/form3", KVVersion: 2}).Credentials()
+
+		thenEquals(t, assertions{
+			{actual: err, expected: nil, name: "Error"},
+			{actual: credentials, expected: Credentials{Token: "versioned"}, name: "Credentials"},
+		})
+	})
+
+	t.Run("When secret cannot be read then Vault errors are returned", func(t *testing.T) {
+		_, forbidden := (&VaultCredentials{Address: server.URL, Token: "guest", Path: "secret/form3"}).Credentials()
+		_, missing := (&VaultCredentials{Address: server.URL, Token: "root", Path: "secret/missing"}).Credentials()
+		_, noField := (&VaultCredentials{Address: server.URL, Token: "root", Path: "secret/form3", Field: "password"}).Credentials()
+
+		thenEquals(t, assertions{
+			{actual: forbidden.Error(), expected: "vault: permission denied", name: "Forbidden"},
+			{actual: missing.Error(), expected: "vault: 404 Not Found", name: "Missing"},
+			{actual: noField.Error(), expected: "vault: secret secret/form3 has no password", name: "NoField"},
+		})
+	})
+}
+
+// TestVaultCredentials_DevServer reads a secret from the Vault container of docker-compose. It is skipped unless
+// VAULT_ADDR and VAULT_TOKEN are set.
+func TestVaultCredentials_DevServer(t *testing.T) {
+	address, token := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")
+	if address == "" || token == "" {
+		t.Skip("VAULT_ADDR and VAULT_TOKEN are not set")
+	}
+
+	req, err := http.NewRequest(http.MethodPut, address+"/v1/secret/form3-client-test", bytes.NewBufferString(`{"token":"rotated"}`))
+	if err != nil {
+		t.Fatalf("error while creating request, %s", err.Error())
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("error while writing secret, %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("error while writing secret, status %d: %s", resp.StatusCode, body)
+	}
+
+	credentials, err := (&VaultCredentials{Path: "secret/form3-client-test"}).Credentials()
+
+	thenEquals(t, assertions{
+		{actual: err, expected: nil, name: "Error"},
+		{actual: credentials.Token, expected: "rotated", name: "Token"},
+	})
+}